@@ -0,0 +1,499 @@
+package conf
+
+import (
+	"fmt"
+	"os"
+	pathpkg "path"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// Config 控制器的配置,通过 -config 指定的yaml文件加载,未配置的字段使用默认值
+type Config struct {
+	// 工作空间启动的最长时间,调用方的超时时间更短时以调用方为准,超时未就绪的Pod会被删除
+	StartTimeout metav1.Duration `json:"startTimeout"`
+	// DeleteSpace最多等待删除完成的时间,超过后返回当前进度
+	DeleteWaitTimeout metav1.Duration `json:"deleteWaitTimeout"`
+	// 工作空间后端,pod直接创建Pod,statefulset为每个工作空间创建单副本StatefulSet,被驱逐的Pod会自动重建
+	Backend    string           `json:"backend"`
+	Webhook    WebhookConfig    `json:"webhook"`
+	Readiness  ReadinessConfig  `json:"readiness"`
+	GC         GCConfig         `json:"gc"`
+	Trash      TrashConfig      `json:"trash"`
+	WarmPool   WarmPoolConfig   `json:"warmPool"`
+	PrePull    PrePullConfig    `json:"prePull"`
+	Catalog    CatalogConfig    `json:"catalog"`
+	Upgrade    UpgradeConfig    `json:"upgrade"`
+	Scheduling SchedulingConfig `json:"scheduling"`
+	Admission  AdmissionConfig  `json:"admission"`
+	Heal       HealConfig       `json:"heal"`
+	Drain      DrainConfig      `json:"drain"`
+	Seed       SeedConfig       `json:"seed"`
+	Git        GitConfig        `json:"git"`
+	// devcontainer.json的转换配置
+	Devcontainer DevcontainerConfig `json:"devcontainer"`
+	Sidecars     SidecarConfig      `json:"sidecars"`
+	Profile      ProfileConfig      `json:"profile"`
+	Preview      PreviewConfig      `json:"preview"`
+	SSH          SSHConfig          `json:"ssh"`
+	Exec         ExecConfig         `json:"exec"`
+}
+
+// ExecConfig execSpace配置,支持人员通过gRPC在工作空间容器中执行命令或打开终端,
+// 每次执行都记录审计日志和工作空间Pod上的事件。调用方在metadata中携带操作人和令牌,
+// 令牌保存在TokenSecret中,key为操作人
+type ExecConfig struct {
+	Enabled bool `json:"enabled"`
+	// 允许执行的操作人,为空时拒绝所有操作人
+	Operators []string `json:"operators"`
+	// 控制器命名空间中保存操作人令牌的Secret
+	TokenSecret string `json:"tokenSecret"`
+	// 是否要求填写原因
+	RequireReason bool `json:"requireReason"`
+	// 没有指定命令时启动的shell
+	Shell string `json:"shell"`
+	// 单次会话的最长时间,超时后断开与API Server的exec连接终止会话
+	MaxDuration metav1.Duration `json:"maxDuration"`
+}
+
+// SSHConfig SSH网关配置,用户通过 ssh <工作空间>@<网关> 连接工作空间,网关使用工作空间所有者注册的公钥认证,
+// 再以网关自己的密钥连接工作空间中的sshd,工作空间镜像需要在Port上运行sshd
+type SSHConfig struct {
+	Enabled bool `json:"enabled"`
+	// 网关的监听地址
+	Address string `json:"address"`
+	// 用户访问网关的地址,例如ssh.cloud-ide.example.com:2222,用于在工作空间信息中返回ssh命令
+	PublicAddress string `json:"publicAddress"`
+	// 工作空间中sshd的端口和登录用户
+	Port int32  `json:"port"`
+	User string `json:"user"`
+	// 存储卷中authorized_keys的路径,启动时由init容器写入网关和所有者的公钥
+	AuthorizedKeysPath string `json:"authorizedKeysPath"`
+	// 写入authorized_keys的init容器镜像,需要包含sh、stat、chmod和chown
+	Image string `json:"image"`
+	// 握手(包括认证)的超时时间,超时未完成握手的连接被关闭
+	HandshakeTimeout metav1.Duration `json:"handshakeTimeout"`
+	// 同时处理的最大连接数,超过后新连接直接关闭
+	MaxConnections int `json:"maxConnections"`
+}
+
+// PreviewConfig 端口预览配置,exposePort为工作空间中的端口创建Service和Ingress,
+// 预览地址为 <端口>-<随机串>.<Domain>,需要将*.<Domain>解析到Ingress控制器
+type PreviewConfig struct {
+	Enabled          bool   `json:"enabled"`
+	Domain           string `json:"domain"`
+	IngressClassName string `json:"ingressClassName"`
+	// 泛域名证书,为空时使用http
+	TLSSecretName string `json:"tlsSecretName"`
+	// 私有预览的Ingress注解,例如nginx的auth-url,由认证服务校验访问者是否可以访问该工作空间,启用预览时必须配置
+	PrivateAnnotations map[string]string `json:"privateAnnotations"`
+	// 共享链接的Ingress注解
+	SharedAnnotations map[string]string `json:"sharedAnnotations"`
+}
+
+// ProfileConfig 用户配置,工作空间启动时由init容器将用户的dotfiles和code-server配置同步到存储卷中
+type ProfileConfig struct {
+	// 执行同步的init容器镜像,需要包含sh、cp、stat和chown
+	Image string `json:"image"`
+	// 存储卷中同步的目标目录,为空时同步到存储卷根目录
+	Dir string `json:"dir"`
+}
+
+// SidecarConfig 工作空间可以使用的附加服务(数据库、缓存等),WorkspaceInfo通过目录中的ID声明,
+// 附加服务与工作空间在同一个Pod中,共享网络,可以通过localhost访问
+type SidecarConfig struct {
+	Catalog map[string]SidecarTemplate `json:"catalog"`
+}
+
+// SidecarTemplate 附加服务目录中的一项,例如postgres、redis
+type SidecarTemplate struct {
+	Image string   `json:"image"`
+	Args  []string `json:"args"`
+	// 服务监听的端口,不能与工作空间的端口冲突
+	Ports []int32 `json:"ports"`
+	// 默认环境变量,调用方指定的同名变量优先
+	Env map[string]string `json:"env"`
+	// 调用方没有指定资源时使用的默认值
+	CPU    string `json:"cpu"`
+	Memory string `json:"memory"`
+	// 调用方可以指定的最大资源,为空时不限制
+	MaxCPU    string `json:"maxCpu"`
+	MaxMemory string `json:"maxMemory"`
+	// 需要持久化的数据目录,为空时不能持久化
+	DataPath       string    `json:"dataPath"`
+	ReadinessProbe *v1.Probe `json:"readinessProbe"`
+}
+
+// GitConfig 创建工作空间时克隆Git仓库的配置
+type GitConfig struct {
+	// 执行克隆的init容器镜像,需要包含sh、git和ssh
+	Image string `json:"image"`
+}
+
+// DevcontainerConfig devcontainer.json的转换配置
+type DevcontainerConfig struct {
+	// remoteUser允许使用的uid(root为0),不在列表中的remoteUser被忽略,以镜像的默认用户运行,为空时忽略所有remoteUser
+	AllowedUIDs []int64 `json:"allowedUIDs"`
+}
+
+// SeedConfig 存储卷初始化配置,工作空间第一次启动时由init容器将种子目录复制到存储卷中,
+// 复制完成后在存储卷中写入标记文件,之后的启动不再复制。镜像目录中的镜像可以单独配置
+type SeedConfig struct {
+	// 镜像中的种子目录,为空时不复制
+	Dir string `json:"dir"`
+	// 包含种子目录的镜像,为空时从工作空间镜像中复制,镜像中需要包含sh和cp
+	Image string `json:"image"`
+	// 存储卷根目录下的标记文件名
+	Marker string `json:"marker"`
+}
+
+// DrainConfig 节点维护配置,节点被封锁(cordon)后通知用户保存,等待NoticePeriod后将工作空间迁移到其它节点
+type DrainConfig struct {
+	// 为每个工作空间创建PodDisruptionBudget,驱逐被阻止,由控制器在通知后迁移工作空间
+	PDB     bool `json:"pdb"`
+	Enabled bool `json:"enabled"`
+	// 检查被封锁节点的间隔
+	Interval metav1.Duration `json:"interval"`
+	// 通知用户后到停止工作空间之间的时间,供code-server和用户保存编辑器状态
+	NoticePeriod metav1.Duration `json:"noticePeriod"`
+	// 工作空间Pod(包括迁移后的Pod)的优雅终止时间,停止或迁移时code-server收到SIGTERM后保存状态
+	GracePeriod metav1.Duration `json:"gracePeriod"`
+	// 停止前在工作空间容器中执行的preStop命令,为空时不设置
+	PreStop []string `json:"preStop"`
+	// 同时迁移的工作空间数量
+	MaxConcurrentMigrations int `json:"maxConcurrentMigrations"`
+}
+
+// HealConfig 自动恢复配置,期望运行的工作空间的Pod意外消失或失败时重新创建
+type HealConfig struct {
+	Enabled bool `json:"enabled"`
+	// 检查工作空间的间隔
+	Interval metav1.Duration `json:"interval"`
+	// 两次自动重启之间的最短间隔,每多重启一次翻倍,最长为MaxBackoff
+	InitialBackoff metav1.Duration `json:"initialBackoff"`
+	MaxBackoff     metav1.Duration `json:"maxBackoff"`
+	// Window时间内最多自动重启的次数,超过后停止工作空间
+	MaxRestarts int             `json:"maxRestarts"`
+	Window      metav1.Duration `json:"window"`
+}
+
+// AdmissionConfig 准入队列配置,资源不足时启动请求按调度配置分层排队,资源释放后自动启动
+type AdmissionConfig struct {
+	Enabled bool `json:"enabled"`
+	// 最长排队时间,超过后请求出队
+	MaxWait metav1.Duration `json:"maxWait"`
+	// 检查队首Pod是否已经调度的间隔
+	PollInterval metav1.Duration `json:"pollInterval"`
+}
+
+// SchedulingConfig 工作空间的调度配置,WorkspaceInfo通过名称选择一个调度配置
+type SchedulingConfig struct {
+	// 没有指定调度配置时使用的配置,为空时不设置任何调度约束
+	DefaultProfile string                       `json:"defaultProfile"`
+	Profiles       map[string]SchedulingProfile `json:"profiles"`
+}
+
+// SchedulingProfile 一组调度约束,例如free、pro、gpu-less-highmem
+type SchedulingProfile struct {
+	NodeSelector map[string]string `json:"nodeSelector"`
+	Affinity     *v1.Affinity      `json:"affinity"`
+	Tolerations  []v1.Toleration   `json:"tolerations"`
+	// labelSelector为空时默认匹配所有工作空间的Pod
+	TopologySpreadConstraints []v1.TopologySpreadConstraint `json:"topologySpreadConstraints"`
+	PriorityClassName         string                        `json:"priorityClassName"`
+}
+
+// UpgradeConfig 镜像升级配置,运行中的工作空间在下次停止后或维护窗口内使用新镜像重启
+type UpgradeConfig struct {
+	// 维护窗口,Duration为0时不主动重启,运行中的工作空间在下次停止后才会升级
+	Window MaintenanceWindow `json:"window"`
+	// 检查是否有需要重启的工作空间的间隔
+	CheckInterval metav1.Duration `json:"checkInterval"`
+	// 每次检查最多重启的工作空间数量
+	MaxRestarts int `json:"maxRestarts"`
+}
+
+// MaintenanceWindow 每天的维护窗口
+type MaintenanceWindow struct {
+	// 开始时间,HH:MM
+	Start    string          `json:"start"`
+	Duration metav1.Duration `json:"duration"`
+	// 时区,例如Asia/Shanghai,为空时使用UTC
+	Timezone string `json:"timezone"`
+}
+
+// CatalogConfig 镜像目录配置,启用后工作空间只能使用目录中的镜像或白名单中的镜像
+type CatalogConfig struct {
+	Enabled bool `json:"enabled"`
+	// 保存镜像目录的ConfigMap,与工作空间在同一个命名空间
+	ConfigMap string `json:"configMap"`
+	// 目录之外允许使用的镜像,以*结尾时按前缀匹配
+	AllowedImages []string `json:"allowedImages"`
+	// 是否将镜像tag解析为digest
+	ResolveDigest bool `json:"resolveDigest"`
+	// digest解析结果的缓存时间
+	DigestCacheTTL metav1.Duration `json:"digestCacheTTL"`
+}
+
+// PrePullConfig 镜像预拉取配置,控制器维护一个DaemonSet,在每个节点上提前拉取镜像
+type PrePullConfig struct {
+	Enabled bool `json:"enabled"`
+	// 目录之外需要预拉取的镜像,启用镜像目录时目录中的镜像都会被预拉取
+	Images []string `json:"images"`
+	// 只在匹配的节点上预拉取,为空表示所有节点
+	NodeSelector map[string]string `json:"nodeSelector"`
+	// 预拉取Pod的容忍,工作空间节点有污点时需要配置,通常与调度配置中的容忍相同
+	Tolerations []v1.Toleration `json:"tolerations"`
+	// 检查DaemonSet是否与配置一致的间隔
+	SyncInterval metav1.Duration `json:"syncInterval"`
+}
+
+// WarmPoolConfig 预热池配置,为常用镜像提前启动Pod,工作空间启动时占用其节点,省去调度和拉取镜像的时间
+type WarmPoolConfig struct {
+	Enabled bool `json:"enabled"`
+	// 补充预热Pod的间隔,预热Pod被占用后会立即补充
+	RefillInterval metav1.Duration `json:"refillInterval"`
+	Pools          []WarmPool      `json:"pools"`
+}
+
+// WarmPool 一个镜像的预热池
+type WarmPool struct {
+	Image string `json:"image"`
+	Size  int    `json:"size"`
+	// 预热Pod请求的资源,应与工作空间请求的资源相同,这样工作空间才能使用预热Pod释放的资源
+	CPU    string `json:"cpu"`
+	Memory string `json:"memory"`
+}
+
+// TrashConfig 回收站配置,默认关闭,设置Retention后删除的工作空间保留Retention时间后才会被彻底删除
+type TrashConfig struct {
+	// 保留时间,为0时DeleteSpace直接彻底删除工作空间
+	Retention metav1.Duration `json:"retention"`
+	// 检查过期工作空间的间隔
+	PurgeInterval metav1.Duration `json:"purgeInterval"`
+}
+
+// WebhookConfig 工作空间生命周期事件的webhook通知配置
+type WebhookConfig struct {
+	// 保存待投递事件的ConfigMap,位于控制器的命名空间,控制器重启或切换leader后继续投递
+	QueueConfigMap string `json:"queueConfigMap"`
+	// 最多保存的待投递任务数,超过后丢弃最早的事件,ConfigMap最大为1MiB,每个任务约1KiB
+	MaxPending int `json:"maxPending"`
+	// 单个事件最多重试次数,超过后丢弃
+	MaxRetries int `json:"maxRetries"`
+	// 第一次重试的等待时间,之后每次翻倍,最多为MaxBackoff
+	InitialBackoff metav1.Duration `json:"initialBackoff"`
+	MaxBackoff     metav1.Duration `json:"maxBackoff"`
+	// 单次HTTP请求超时时间
+	Timeout       metav1.Duration       `json:"timeout"`
+	Subscriptions []WebhookSubscription `json:"subscriptions"`
+}
+
+// ReadinessConfig 工作空间容器的HTTP就绪探针,探测通过后才认为code-server可以访问
+type ReadinessConfig struct {
+	Path string `json:"path"`
+	// 探测端口,为0时使用工作空间的端口
+	Port                int32 `json:"port"`
+	InitialDelaySeconds int32 `json:"initialDelaySeconds"`
+	PeriodSeconds       int32 `json:"periodSeconds"`
+	FailureThreshold    int32 `json:"failureThreshold"`
+}
+
+// 垃圾回收对发现的问题资源采取的动作
+const (
+	GCActionDelete = "delete"
+	GCActionReport = "report"
+)
+
+// GCConfig 孤儿资源垃圾回收配置
+type GCConfig struct {
+	Enabled  bool            `json:"enabled"`
+	Interval metav1.Duration `json:"interval"`
+	// 为true时只报告(日志和Event),不删除任何资源
+	DryRun bool `json:"dryRun"`
+	// Pending超过该时间且不在启动流程中的Pod被认为无法启动
+	PendingTimeout metav1.Duration `json:"pendingTimeout"`
+	// 从未成功启动过且没有Pod的PVC,创建超过该时间后被认为是创建失败遗留的
+	UnusedPVCAge metav1.Duration `json:"unusedPVCAge"`
+	Policy       GCPolicy        `json:"policy"`
+}
+
+// GCPolicy 每一类问题资源的处理动作,delete或report
+type GCPolicy struct {
+	// PVC不存在的Pod
+	OrphanPod string `json:"orphanPod"`
+	// PVC正在删除但仍在使用它的Pod
+	TerminatingClaimPod string `json:"terminatingClaimPod"`
+	// 长时间Pending的Pod
+	PendingPod string `json:"pendingPod"`
+	// 从未使用过的PVC
+	UnusedPVC string `json:"unusedPVC"`
+	// 所属工作空间不存在的Service、Secret、Ingress、PodDisruptionBudget
+	OrphanObject string `json:"orphanObject"`
+}
+
+// WebhookSubscription 一个webhook订阅
+type WebhookSubscription struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// 用于HMAC-SHA256签名的密钥
+	Secret string `json:"secret"`
+	// 订阅的事件类型,为空表示订阅全部事件
+	Events []string `json:"events"`
+}
+
+func Default() *Config {
+	return &Config{
+		StartTimeout:      metav1.Duration{Duration: time.Minute * 5},
+		DeleteWaitTimeout: metav1.Duration{Duration: time.Second * 20},
+		Backend:           "pod",
+		Webhook: WebhookConfig{
+			QueueConfigMap: "cloud-ide-webhook-queue",
+			MaxPending:     500,
+			MaxRetries:     10,
+			InitialBackoff: metav1.Duration{Duration: time.Second},
+			MaxBackoff:     metav1.Duration{Duration: time.Minute * 10},
+			Timeout:        metav1.Duration{Duration: time.Second * 10},
+		},
+		Readiness: ReadinessConfig{
+			// code-server自带的健康检查接口
+			Path:             "/healthz",
+			PeriodSeconds:    2,
+			FailureThreshold: 3,
+		},
+		SSH: SSHConfig{
+			Address:            ":2222",
+			Port:               2022,
+			User:               "root",
+			AuthorizedKeysPath: ".ssh/authorized_keys",
+			Image:              "busybox:1.36",
+			HandshakeTimeout:   metav1.Duration{Duration: time.Second * 30},
+			MaxConnections:     500,
+		},
+		Exec: ExecConfig{
+			Shell:       "sh",
+			MaxDuration: metav1.Duration{Duration: time.Hour},
+		},
+		Profile: ProfileConfig{
+			Image: "busybox:1.36",
+		},
+		Git: GitConfig{
+			Image: "alpine/git:2.36.3",
+		},
+		Seed: SeedConfig{
+			Marker: ".cloud-ide-seeded",
+		},
+		Drain: DrainConfig{
+			Interval:                metav1.Duration{Duration: time.Second * 30},
+			NoticePeriod:            metav1.Duration{Duration: time.Minute * 2},
+			GracePeriod:             metav1.Duration{Duration: time.Minute},
+			PreStop:                 []string{"sh", "-c", "sleep 5; sync"},
+			MaxConcurrentMigrations: 5,
+		},
+		Heal: HealConfig{
+			Enabled:        true,
+			Interval:       metav1.Duration{Duration: time.Second * 30},
+			InitialBackoff: metav1.Duration{Duration: time.Second * 10},
+			MaxBackoff:     metav1.Duration{Duration: time.Minute * 5},
+			MaxRestarts:    5,
+			Window:         metav1.Duration{Duration: time.Hour},
+		},
+		Admission: AdmissionConfig{
+			MaxWait:      metav1.Duration{Duration: time.Minute * 30},
+			PollInterval: metav1.Duration{Duration: time.Second},
+		},
+		Upgrade: UpgradeConfig{
+			CheckInterval: metav1.Duration{Duration: time.Minute * 5},
+			MaxRestarts:   5,
+		},
+		Catalog: CatalogConfig{
+			ConfigMap:      "cloud-ide-image-catalog",
+			ResolveDigest:  true,
+			DigestCacheTTL: metav1.Duration{Duration: time.Minute * 10},
+		},
+		PrePull: PrePullConfig{
+			SyncInterval: metav1.Duration{Duration: time.Minute * 5},
+		},
+		WarmPool: WarmPoolConfig{
+			RefillInterval: metav1.Duration{Duration: time.Second * 30},
+		},
+		Trash: TrashConfig{
+			PurgeInterval: metav1.Duration{Duration: time.Minute * 10},
+		},
+		GC: GCConfig{
+			Enabled:        true,
+			Interval:       metav1.Duration{Duration: time.Minute * 10},
+			DryRun:         true,
+			PendingTimeout: metav1.Duration{Duration: time.Minute * 30},
+			UnusedPVCAge:   metav1.Duration{Duration: time.Hour},
+			Policy: GCPolicy{
+				OrphanPod:           GCActionDelete,
+				TerminatingClaimPod: GCActionDelete,
+				PendingPod:          GCActionDelete,
+				UnusedPVC:           GCActionReport,
+				OrphanObject:        GCActionDelete,
+			},
+		},
+	}
+}
+
+// Load 从文件中加载配置,path为空时返回默认配置
+func Load(path string) (*Config, error) {
+	cfg := Default()
+	if path == "" {
+		return cfg, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.UnmarshalStrict(data, cfg); err != nil {
+		return nil, err
+	}
+	if cfg.Webhook.QueueConfigMap == "" || cfg.Webhook.MaxPending <= 0 {
+		return nil, fmt.Errorf("webhook queueConfigMap and maxPending are required")
+	}
+	// PDB阻止驱逐后需要由控制器迁移工作空间,否则kubectl drain会一直等待
+	if cfg.Drain.PDB && !cfg.Drain.Enabled {
+		return nil, fmt.Errorf("drain pdb requires drain enabled")
+	}
+	if cfg.Drain.Enabled && cfg.Drain.MaxConcurrentMigrations <= 0 {
+		return nil, fmt.Errorf("drain maxConcurrentMigrations must be positive")
+	}
+	// 同步目录需要在存储卷中
+	if dir := cfg.Profile.Dir; pathpkg.IsAbs(dir) || strings.HasPrefix(pathpkg.Clean(dir), "..") {
+		return nil, fmt.Errorf("invalid profile dir %q", dir)
+	}
+	if dir := cfg.SSH.AuthorizedKeysPath; cfg.SSH.Enabled && (dir == "" || pathpkg.IsAbs(dir) || strings.HasPrefix(pathpkg.Clean(dir), "..")) {
+		return nil, fmt.Errorf("invalid ssh authorizedKeysPath %q", dir)
+	}
+	if cfg.SSH.Enabled && (cfg.SSH.HandshakeTimeout.Duration <= 0 || cfg.SSH.MaxConnections <= 0) {
+		return nil, fmt.Errorf("ssh handshakeTimeout and maxConnections must be positive")
+	}
+	if cfg.Exec.Enabled && (cfg.Exec.Shell == "" || cfg.Exec.MaxDuration.Duration <= 0 || cfg.Exec.TokenSecret == "") {
+		return nil, fmt.Errorf("exec shell, maxDuration and tokenSecret are required")
+	}
+	if cfg.Preview.Enabled && cfg.Preview.Domain == "" {
+		return nil, fmt.Errorf("preview domain is required")
+	}
+	// 没有认证注解时私有预览与共享链接相同,任何人都可以访问
+	if cfg.Preview.Enabled && len(cfg.Preview.PrivateAnnotations) == 0 {
+		return nil, fmt.Errorf("preview privateAnnotations is required")
+	}
+	for id, tpl := range cfg.Sidecars.Catalog {
+		if tpl.Image == "" {
+			return nil, fmt.Errorf("sidecar %q has no image", id)
+		}
+	}
+	if p := cfg.Scheduling.DefaultProfile; p != "" {
+		if _, ok := cfg.Scheduling.Profiles[p]; !ok {
+			return nil, fmt.Errorf("default scheduling profile %q not found", p)
+		}
+	}
+
+	return cfg, nil
+}
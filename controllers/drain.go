@@ -0,0 +1,201 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/conf"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/backend"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/events"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/webhook"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/workspace"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+/*
+	节点维护: 节点被封锁(kubectl cordon/drain)后,通过Event和webhook通知该节点上的工作空间,
+	通知期内code-server和用户可以保存编辑器状态,通知期结束后停止工作空间并在其它节点上重新启动。
+	工作空间的PodDisruptionBudget不允许驱逐,kubectl drain会一直等待,直到工作空间被迁移。
+	还没有就绪的工作空间没有需要保存的状态,直接迁移;通知期内节点解除封锁时取消迁移。
+	每个工作空间的迁移需要等待旧Pod删除,最多MaxConcurrentMigrations个工作空间同时迁移
+*/
+
+// DrainCoordinator 迁移被封锁节点上的工作空间,实现了manager.Runnable,只在leader上运行
+type DrainCoordinator struct {
+	client       client.Client
+	backend      backend.WorkspaceBackend
+	recorder     record.EventRecorder
+	notifier     *webhook.Notifier
+	cfg          conf.DrainConfig
+	startTimeout time.Duration
+}
+
+func NewDrainCoordinator(client client.Client, backend backend.WorkspaceBackend, recorder record.EventRecorder,
+	notifier *webhook.Notifier, cfg conf.DrainConfig, startTimeout time.Duration) *DrainCoordinator {
+	return &DrainCoordinator{
+		client:       client,
+		backend:      backend,
+		recorder:     recorder,
+		notifier:     notifier,
+		cfg:          cfg,
+		startTimeout: startTimeout,
+	}
+}
+
+func (d *DrainCoordinator) Start(ctx context.Context) error {
+	ticker := time.NewTicker(d.cfg.Interval.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			d.Sync(ctx)
+		}
+	}
+}
+
+// Sync 通知或迁移被封锁节点上的工作空间
+func (d *DrainCoordinator) Sync(ctx context.Context) {
+	logger := log.FromContext(ctx).WithName("drain")
+	nodes := &v1.NodeList{}
+	if err := d.client.List(ctx, nodes); err != nil {
+		logger.Error(err, "list nodes")
+		return
+	}
+	cordoned := make(map[string]bool)
+	for i := range nodes.Items {
+		if nodes.Items[i].Spec.Unschedulable {
+			cordoned[nodes.Items[i].Name] = true
+		}
+	}
+
+	pods := &v1.PodList{}
+	if err := d.client.List(ctx, pods, client.MatchingLabels{workspace.LabelKind: workspace.KindCloudIde}); err != nil {
+		logger.Error(err, "list pods")
+		return
+	}
+	var migrating []*v1.Pod
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName == "" || pod.DeletionTimestamp != nil || pod.Annotations[workspace.AnnotationStopReason] != "" {
+			continue
+		}
+		notice := pod.Annotations[workspace.AnnotationDrainNotice]
+		if !cordoned[pod.Spec.NodeName] {
+			if notice != "" {
+				// 节点已经解除封锁,取消迁移
+				if err := d.annotate(ctx, pod, nil); err != nil {
+					logger.Error(err, "clear drain notice", "workspace", workspace.Name(pod))
+				}
+			}
+			continue
+		}
+
+		switch {
+		case !workspace.IsPodReady(pod):
+		case notice == "":
+			if err := d.notice(ctx, pod); err != nil {
+				logger.Error(err, "drain notice", "workspace", workspace.Name(pod))
+			}
+			continue
+		default:
+			noticedAt, err := time.Parse(time.RFC3339, notice)
+			if err == nil && time.Since(noticedAt) < d.cfg.NoticePeriod.Duration {
+				continue
+			}
+		}
+
+		migrating = append(migrating, pod)
+	}
+	d.migrateAll(ctx, migrating)
+}
+
+// migrateAll 并发迁移工作空间,等待全部完成后返回,避免下次Sync重复迁移
+func (d *DrainCoordinator) migrateAll(ctx context.Context, pods []*v1.Pod) {
+	logger := log.FromContext(ctx).WithName("drain")
+	ch := make(chan *v1.Pod)
+	var wg sync.WaitGroup
+	for i := 0; i < d.cfg.MaxConcurrentMigrations && i < len(pods); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for pod := range ch {
+				if err := d.migrate(ctx, pod); err != nil {
+					logger.Error(err, "migrate workspace", "workspace", workspace.Name(pod))
+					d.recorder.Eventf(pod, v1.EventTypeWarning, events.ReasonCreatePodFailed, "migrate workspace failed: %v", err)
+				}
+			}
+		}()
+	}
+	for _, pod := range pods {
+		ch <- pod
+	}
+	close(ch)
+	wg.Wait()
+}
+
+// notice 记录通知时间并通知用户,通知期结束后迁移
+func (d *DrainCoordinator) notice(ctx context.Context, pod *v1.Pod) error {
+	now := time.Now()
+	value := now.UTC().Format(time.RFC3339)
+	if err := d.annotate(ctx, pod, &value); err != nil {
+		return err
+	}
+	migrateAt := now.Add(d.cfg.NoticePeriod.Duration)
+	message := fmt.Sprintf("node %s is under maintenance, workspace will be restarted on another node at %s",
+		pod.Spec.NodeName, migrateAt.Format(time.RFC3339))
+	d.recorder.Event(pod, v1.EventTypeWarning, events.ReasonDrainNotice, message)
+	d.notifier.Notify(webhook.Event{
+		Type:      webhook.EventWorkspaceDraining,
+		Workspace: workspace.Name(pod),
+		Namespace: pod.Namespace,
+		NodeName:  pod.Spec.NodeName,
+		Phase:     string(pod.Status.Phase),
+		Reason:    "NodeCordoned",
+		Expected:  true,
+		Message:   message,
+	})
+
+	return nil
+}
+
+// migrate 停止工作空间并在其它节点上重新启动,被封锁的节点不会再被调度
+func (d *DrainCoordinator) migrate(ctx context.Context, pod *v1.Pod) error {
+	claim := workspace.ClaimName(pod)
+	if claim == "" {
+		return fmt.Errorf("pod has no workspace pvc")
+	}
+	pvc := &v1.PersistentVolumeClaim{}
+	if err := d.client.Get(ctx, client.ObjectKey{Namespace: pod.Namespace, Name: claim}, pvc); err != nil {
+		return err
+	}
+
+	// 旧版本创建的Pod没有preStop和优雅终止时间,迁移后的Pod补上
+	tpl := workspace.PodTemplate(pod)
+	workspace.SetGracefulStop(tpl, d.cfg.GracePeriod.Duration, d.cfg.PreStop)
+	if err := restartWorkspace(ctx, d.backend, pvc, tpl, workspace.StopReasonDrain, d.startTimeout); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("workspace migrated", "workspace", pvc.Name, "node", pod.Spec.NodeName)
+	d.recorder.Eventf(pvc, v1.EventTypeNormal, events.ReasonWorkspaceMigrated, "restarted away from node %s", pod.Spec.NodeName)
+
+	return nil
+}
+
+// annotate 设置或删除(value为nil)通知时间
+func (d *DrainCoordinator) annotate(ctx context.Context, pod *v1.Pod, value *string) error {
+	val := "null"
+	if value != nil {
+		val = fmt.Sprintf("%q", *value)
+	}
+	patch := []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:%s}}}`, workspace.AnnotationDrainNotice, val))
+
+	return client.IgnoreNotFound(d.client.Patch(ctx, pod, client.RawPatch(types.MergePatchType, patch)))
+}
@@ -0,0 +1,192 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/conf"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/events"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/workspace"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+/*
+	PVC即工作空间,PVC不存在说明工作空间已经被删除,垃圾回收定期检查以下问题资源:
+	1. PVC不存在的Pod
+	2. PVC正在删除(Terminating)但仍然挂载它的Pod,Pod不删除PVC就无法删除
+	3. 长时间Pending且不在启动流程中的Pod
+	4. 从未成功启动过且没有Pod的PVC,通常是CreateSpace创建Pod失败遗留的
+	5. 所属工作空间不存在的Service、Secret、Ingress、PodDisruptionBudget
+	每一类资源按照配置的策略删除或只报告,DryRun时全部只报告
+*/
+
+// 问题资源的类别
+const (
+	findingOrphanPod           = "OrphanPod"
+	findingTerminatingClaimPod = "TerminatingClaimPod"
+	findingPendingPod          = "PendingPod"
+	findingUnusedPVC           = "UnusedPVC"
+	findingOrphanObject        = "OrphanObject"
+)
+
+type finding struct {
+	kind   string
+	obj    client.Object
+	reason string
+}
+
+//+kubebuilder:rbac:groups="",resources=services;secrets,verbs=get;list;watch;delete
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;delete
+//+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;delete
+
+// GarbageCollector 定期清理孤儿资源,实现了manager.Runnable,只在leader上运行
+type GarbageCollector struct {
+	client   client.Client
+	recorder record.EventRecorder
+	cfg      conf.GCConfig
+}
+
+func NewGarbageCollector(client client.Client, recorder record.EventRecorder, cfg conf.GCConfig) *GarbageCollector {
+	return &GarbageCollector{client: client, recorder: recorder, cfg: cfg}
+}
+
+func (g *GarbageCollector) Start(ctx context.Context) error {
+	ticker := time.NewTicker(g.cfg.Interval.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			g.Collect(ctx)
+		}
+	}
+}
+
+// Collect 执行一次垃圾回收,返回发现的问题资源数量
+func (g *GarbageCollector) Collect(ctx context.Context) int {
+	logger := log.FromContext(ctx).WithName("gc")
+	findings, err := g.find(ctx)
+	if err != nil {
+		logger.Error(err, "find garbage")
+		return 0
+	}
+
+	for _, f := range findings {
+		action := g.action(f.kind)
+		if g.cfg.DryRun || action != conf.GCActionDelete {
+			logger.Info("garbage detected", "kind", f.kind, "object", client.ObjectKeyFromObject(f.obj), "reason", f.reason, "dryRun", g.cfg.DryRun)
+			g.recorder.Eventf(f.obj, v1.EventTypeWarning, events.ReasonOrphanDetected, "%s: %s", f.kind, f.reason)
+			continue
+		}
+
+		if err := g.delete(ctx, f.obj); err != nil && !errors.IsNotFound(err) {
+			logger.Error(err, "delete garbage", "kind", f.kind, "object", client.ObjectKeyFromObject(f.obj))
+			continue
+		}
+		logger.Info("garbage deleted", "kind", f.kind, "object", client.ObjectKeyFromObject(f.obj), "reason", f.reason)
+		g.recorder.Eventf(f.obj, v1.EventTypeNormal, events.ReasonGarbageCollected, "%s: %s", f.kind, f.reason)
+	}
+
+	return len(findings)
+}
+
+func (g *GarbageCollector) find(ctx context.Context) ([]finding, error) {
+	// 旧版本创建的PVC没有标签,因此这里列出所有PVC用于判断工作空间是否存在
+	pvcList := &v1.PersistentVolumeClaimList{}
+	if err := g.client.List(ctx, pvcList); err != nil {
+		return nil, err
+	}
+	pvcs := make(map[string]*v1.PersistentVolumeClaim, len(pvcList.Items))
+	for i := range pvcList.Items {
+		pvcs[pvcList.Items[i].Name] = &pvcList.Items[i]
+	}
+
+	selector := client.MatchingLabels{workspace.LabelKind: workspace.KindCloudIde}
+	podList := &v1.PodList{}
+	if err := g.client.List(ctx, podList, selector); err != nil {
+		return nil, err
+	}
+
+	var findings []finding
+	now := time.Now()
+	usedClaims := make(map[string]bool)
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		claim := workspace.ClaimName(pod)
+		usedClaims[claim] = true
+		pvc, ok := pvcs[claim]
+		switch {
+		case !ok:
+			findings = append(findings, finding{findingOrphanPod, pod, "pvc " + claim + " not found"})
+		case pvc.DeletionTimestamp != nil:
+			findings = append(findings, finding{findingTerminatingClaimPod, pod, "pvc " + claim + " is terminating"})
+		case pod.Status.Phase == v1.PodPending && g.pendingTooLong(pod, now):
+			findings = append(findings, finding{findingPendingPod, pod, "pending since " + pod.CreationTimestamp.Format(time.RFC3339)})
+		}
+	}
+
+	for _, pvc := range pvcs {
+		if pvc.Labels[workspace.LabelKind] != workspace.KindCloudIde || pvc.DeletionTimestamp != nil || usedClaims[pvc.Name] ||
+			pvc.Labels[workspace.LabelState] == workspace.StateTrashed {
+			continue
+		}
+		if pvc.Annotations[workspace.AnnotationInitialized] == "" && now.Sub(pvc.CreationTimestamp.Time) > g.cfg.UnusedPVCAge.Duration {
+			findings = append(findings, finding{findingUnusedPVC, pvc, "never started since " + pvc.CreationTimestamp.Format(time.RFC3339)})
+		}
+	}
+
+	objects, err := workspace.ListAuxiliary(ctx, g.client, selector)
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range objects {
+		name := obj.GetLabels()[workspace.LabelWorkspace]
+		if pvc, ok := pvcs[name]; obj.GetDeletionTimestamp() == nil && (!ok || pvc.DeletionTimestamp != nil) {
+			findings = append(findings, finding{findingOrphanObject, obj, "workspace " + name + " not found"})
+		}
+	}
+
+	return findings, nil
+}
+
+// pendingTooLong 启动流程中的Pod由PodReconciler在截止时间后处理,这里只处理没有截止时间或已经超过截止时间的Pod
+func (g *GarbageCollector) pendingTooLong(pod *v1.Pod, now time.Time) bool {
+	if deadline, ok := workspace.StartDeadline(pod); ok && now.Before(deadline) {
+		return false
+	}
+
+	return now.Sub(pod.CreationTimestamp.Time) > g.cfg.PendingTimeout.Duration
+}
+
+func (g *GarbageCollector) action(kind string) string {
+	switch kind {
+	case findingOrphanPod:
+		return g.cfg.Policy.OrphanPod
+	case findingTerminatingClaimPod:
+		return g.cfg.Policy.TerminatingClaimPod
+	case findingPendingPod:
+		return g.cfg.Policy.PendingPod
+	case findingUnusedPVC:
+		return g.cfg.Policy.UnusedPVC
+	case findingOrphanObject:
+		return g.cfg.Policy.OrphanObject
+	}
+
+	return conf.GCActionReport
+}
+
+func (g *GarbageCollector) delete(ctx context.Context, obj client.Object) error {
+	if pod, ok := obj.(*v1.Pod); ok {
+		return workspace.DeletePod(ctx, g.client, pod, workspace.StopReasonGarbage)
+	}
+
+	return g.client.Delete(ctx, obj)
+}
@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/conf"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/events"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/workspace"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("GarbageCollector", func() {
+	var (
+		c        client.Client
+		recorder *record.FakeRecorder
+	)
+
+	BeforeEach(func() {
+		pending := newWorkspacePod("ws-pending", "code-server:v1", time.Hour, v1.PodPending)
+		// 启动截止时间之前的Pending Pod由PodReconciler处理
+		starting := newWorkspacePod("ws-starting", "code-server:v1", time.Hour, v1.PodPending)
+		workspace.SetStarting(starting, time.Now().Add(time.Minute))
+		orphanService := &v1.Service{ObjectMeta: metav1.ObjectMeta{
+			Name:      "ws-deleted",
+			Namespace: testNamespace,
+			Labels: map[string]string{
+				workspace.LabelKind:      workspace.KindCloudIde,
+				workspace.LabelWorkspace: "ws-deleted",
+			},
+		}}
+
+		c = newFakeClient(
+			// PVC不存在的Pod
+			newWorkspacePod("ws-orphan", "code-server:v1", time.Hour, v1.PodRunning),
+			newWorkspacePVC("ws-pending", time.Hour, nil), pending,
+			newWorkspacePVC("ws-starting", time.Hour, nil), starting,
+			newWorkspacePVC("ws-running", time.Hour, nil), newWorkspacePod("ws-running", "code-server:v1", time.Hour, v1.PodRunning),
+			// 从未启动过的PVC,刚创建的PVC和回收站中的PVC不处理
+			newWorkspacePVC("ws-unused", time.Hour*2, nil),
+			newWorkspacePVC("ws-new", time.Minute, nil),
+			newWorkspacePVC("ws-stopped", time.Hour*2, map[string]string{workspace.AnnotationInitialized: "true"}),
+			orphanService,
+		)
+		recorder = record.NewFakeRecorder(20)
+	})
+
+	newCollector := func(dryRun bool) *GarbageCollector {
+		return NewGarbageCollector(c, recorder, conf.GCConfig{
+			DryRun:         dryRun,
+			PendingTimeout: metav1.Duration{Duration: time.Minute * 30},
+			UnusedPVCAge:   metav1.Duration{Duration: time.Hour},
+			Policy: conf.GCPolicy{
+				OrphanPod:           conf.GCActionDelete,
+				TerminatingClaimPod: conf.GCActionDelete,
+				PendingPod:          conf.GCActionDelete,
+				UnusedPVC:           conf.GCActionReport,
+				OrphanObject:        conf.GCActionDelete,
+			},
+		})
+	}
+
+	exists := func(obj client.Object, name string) bool {
+		err := c.Get(context.Background(), client.ObjectKey{Namespace: testNamespace, Name: name}, obj)
+		Expect(client.IgnoreNotFound(err)).To(Succeed())
+		return err == nil
+	}
+
+	It("reports but deletes nothing in dry run", func() {
+		Expect(newCollector(true).Collect(context.Background())).To(Equal(4))
+
+		for _, name := range []string{"ws-orphan", "ws-pending", "ws-starting", "ws-running"} {
+			Expect(exists(&v1.Pod{}, name)).To(BeTrue(), name)
+		}
+		Expect(exists(&v1.PersistentVolumeClaim{}, "ws-unused")).To(BeTrue())
+		Expect(exists(&v1.Service{}, "ws-deleted")).To(BeTrue())
+		Expect(recorder.Events).To(HaveLen(4))
+		for i := 0; i < 4; i++ {
+			Expect(<-recorder.Events).To(ContainSubstring(events.ReasonOrphanDetected))
+		}
+	})
+
+	It("deletes garbage according to the policy", func() {
+		Expect(newCollector(false).Collect(context.Background())).To(Equal(4))
+
+		Expect(exists(&v1.Pod{}, "ws-orphan")).To(BeFalse())
+		Expect(exists(&v1.Pod{}, "ws-pending")).To(BeFalse())
+		Expect(exists(&v1.Service{}, "ws-deleted")).To(BeFalse())
+		// 策略为report的资源只报告
+		Expect(exists(&v1.PersistentVolumeClaim{}, "ws-unused")).To(BeTrue())
+		Expect(exists(&v1.Pod{}, "ws-starting")).To(BeTrue())
+		Expect(exists(&v1.Pod{}, "ws-running")).To(BeTrue())
+		Expect(exists(&v1.PersistentVolumeClaim{}, "ws-new")).To(BeTrue())
+		Expect(exists(&v1.PersistentVolumeClaim{}, "ws-stopped")).To(BeTrue())
+		// 删除的Pod所属的工作空间记录为期望停止,不会被自动恢复
+		Expect(getPVC(c, "ws-pending").Annotations[workspace.AnnotationDesiredState]).To(Equal(workspace.DesiredStopped))
+	})
+})
@@ -0,0 +1,183 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/conf"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/backend"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/events"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/workspace"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+/*
+	自动恢复: 期望运行(PVC上记录为running)的工作空间,Pod消失(节点故障、被驱逐后删除)或失败时,
+	按PVC上保存的模板重新创建。重启之间按指数退避等待,Window内重启次数达到MaxRestarts后
+	将工作空间记录为期望停止,不再恢复。
+	StatefulSet管理的失败Pod由StatefulSet控制器重建,这里不处理
+*/
+
+// reasonPodMissing 期望运行的工作空间没有Pod
+const reasonPodMissing = "PodMissing"
+
+// Healer 重新创建意外停止的工作空间,实现了manager.Runnable,只在leader上运行
+type Healer struct {
+	client       client.Client
+	backend      backend.WorkspaceBackend
+	recorder     record.EventRecorder
+	cfg          conf.HealConfig
+	startTimeout time.Duration
+}
+
+func NewHealer(client client.Client, backend backend.WorkspaceBackend, recorder record.EventRecorder, cfg conf.HealConfig, startTimeout time.Duration) *Healer {
+	return &Healer{
+		client:       client,
+		backend:      backend,
+		recorder:     recorder,
+		cfg:          cfg,
+		startTimeout: startTimeout,
+	}
+}
+
+func (h *Healer) Start(ctx context.Context) error {
+	ticker := time.NewTicker(h.cfg.Interval.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			h.Heal(ctx)
+		}
+	}
+}
+
+// Heal 检查所有期望运行的工作空间
+func (h *Healer) Heal(ctx context.Context) {
+	logger := log.FromContext(ctx).WithName("heal")
+	pvcs := &v1.PersistentVolumeClaimList{}
+	if err := h.client.List(ctx, pvcs, client.MatchingLabels{workspace.LabelKind: workspace.KindCloudIde}); err != nil {
+		logger.Error(err, "list pvc")
+		return
+	}
+
+	for i := range pvcs.Items {
+		pvc := &pvcs.Items[i]
+		if pvc.Annotations[workspace.AnnotationDesiredState] != workspace.DesiredRunning ||
+			pvc.DeletionTimestamp != nil || pvc.Labels[workspace.LabelState] == workspace.StateTrashed {
+			continue
+		}
+		if err := h.heal(ctx, pvc); err != nil {
+			logger.Error(err, "heal workspace", "workspace", pvc.Name)
+		}
+	}
+}
+
+func (h *Healer) heal(ctx context.Context, pvc *v1.PersistentVolumeClaim) error {
+	reason, nodeName := reasonPodMissing, ""
+	pod, err := h.backend.Get(ctx, pvc.Namespace, pvc.Name)
+	switch {
+	case errors.IsNotFound(err):
+	case err != nil:
+		return err
+	case pod.DeletionTimestamp != nil || pod.Status.Phase != v1.PodFailed || workspace.StatefulSetOf(pod) != "":
+		return nil
+	default:
+		reason, nodeName = failedReason(pod), pod.Spec.NodeName
+	}
+
+	history := workspace.RestartHistory(pvc)
+	recent := recentRestarts(history, time.Now().Add(-h.cfg.Window.Duration))
+	if recent >= h.cfg.MaxRestarts {
+		// 频繁失败,不再恢复,失败的Pod保留用于排查
+		if err := workspace.SetDesiredStopped(ctx, h.client, pvc.Namespace, pvc.Name); err != nil {
+			return err
+		}
+		log.FromContext(ctx).Info("workspace restarted too many times, give up", "workspace", pvc.Name, "restarts", recent)
+		h.recorder.Eventf(pvc, v1.EventTypeWarning, events.ReasonHealGaveUp,
+			"restarted %d times in %v, stop healing, last reason: %s", recent, h.cfg.Window.Duration, reason)
+		return nil
+	}
+	if recent > 0 && time.Now().Before(history[len(history)-1].Time.Add(h.backoff(recent))) {
+		return nil
+	}
+
+	tpl, err := workspace.DesiredTemplate(pvc)
+	if err != nil {
+		return fmt.Errorf("invalid pod template: %v", err)
+	}
+	if tpl == nil {
+		return nil
+	}
+	if pod != nil {
+		if err := h.deleteFailed(ctx, pod); err != nil {
+			return err
+		}
+	}
+
+	workspace.SetOwner(tpl, pvc)
+	workspace.SetStarting(tpl, time.Now().Add(h.startTimeout))
+	if err := h.backend.Start(ctx, tpl); err != nil {
+		h.recorder.Eventf(pvc, v1.EventTypeWarning, events.ReasonCreatePodFailed, "heal workspace failed: %v", err)
+		return err
+	}
+	if err := workspace.AddRestart(ctx, h.client, pvc, reason, nodeName); err != nil {
+		return err
+	}
+	log.FromContext(ctx).Info("workspace healed", "workspace", pvc.Name, "reason", reason, "node", nodeName)
+	h.recorder.Eventf(pvc, v1.EventTypeNormal, events.ReasonWorkspaceHealed, "recreated pod, reason: %s", reason)
+
+	return nil
+}
+
+// deleteFailed 删除失败的Pod并等待其删除完成,工作空间仍然期望运行,因此不使用DeletePod
+func (h *Healer) deleteFailed(ctx context.Context, pod *v1.Pod) error {
+	patch := []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, workspace.AnnotationStopReason, workspace.StopReasonHeal))
+	if err := h.client.Patch(ctx, pod, client.RawPatch(types.MergePatchType, patch)); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if err := h.client.Delete(ctx, pod); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+
+	return wait.PollImmediateWithContext(ctx, time.Second, time.Minute, func(ctx context.Context) (bool, error) {
+		err := h.client.Get(ctx, client.ObjectKeyFromObject(pod), &v1.Pod{})
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, client.IgnoreNotFound(err)
+	})
+}
+
+// backoff 最近已经重启n次时,距离上一次重启需要等待的时间
+func (h *Healer) backoff(n int) time.Duration {
+	d := h.cfg.InitialBackoff.Duration
+	for i := 1; i < n && d < h.cfg.MaxBackoff.Duration; i++ {
+		d *= 2
+	}
+	if d > h.cfg.MaxBackoff.Duration {
+		d = h.cfg.MaxBackoff.Duration
+	}
+
+	return d
+}
+
+// recentRestarts since之后的重启次数
+func recentRestarts(history []workspace.RestartRecord, since time.Time) int {
+	n := 0
+	for _, record := range history {
+		if record.Time.After(since) {
+			n++
+		}
+	}
+
+	return n
+}
@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/conf"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/backend"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/events"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/workspace"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Healer", func() {
+	const name = "ws-1"
+
+	var (
+		recorder *record.FakeRecorder
+		tpl      *v1.Pod
+	)
+
+	BeforeEach(func() {
+		recorder = record.NewFakeRecorder(10)
+		tpl = newWorkspacePod(name, "code-server:v1", 0, "")
+	})
+
+	newHealer := func(c client.Client) *Healer {
+		return NewHealer(c, backend.NewPodBackend(c), recorder, conf.HealConfig{
+			InitialBackoff: metav1.Duration{Duration: time.Minute},
+			MaxBackoff:     metav1.Duration{Duration: time.Minute * 10},
+			MaxRestarts:    3,
+			Window:         metav1.Duration{Duration: time.Hour},
+		}, time.Minute)
+	}
+
+	// runningPVC 期望运行并保存了模板的工作空间
+	runningPVC := func(history string) *v1.PersistentVolumeClaim {
+		annotations := map[string]string{
+			workspace.AnnotationDesiredState: workspace.DesiredRunning,
+			workspace.AnnotationPodTemplate:  templateOf(tpl),
+		}
+		if history != "" {
+			annotations[workspace.AnnotationRestartHistory] = history
+		}
+		return newWorkspacePVC(name, time.Hour, annotations)
+	}
+
+	It("recreates a missing pod from the saved template", func() {
+		c := newFakeClient(runningPVC(""))
+		newHealer(c).Heal(context.Background())
+
+		pod := getPod(c, name)
+		Expect(pod).NotTo(BeNil())
+		Expect(workspace.Container(pod).Image).To(Equal("code-server:v1"))
+		_, starting := workspace.StartDeadline(pod)
+		Expect(starting).To(BeTrue())
+
+		history := workspace.RestartHistory(getPVC(c, name))
+		Expect(history).To(HaveLen(1))
+		Expect(history[0].Reason).To(Equal(reasonPodMissing))
+		Expect(recorder.Events).To(Receive(ContainSubstring(events.ReasonWorkspaceHealed)))
+	})
+
+	It("replaces a failed pod and records the failure reason", func() {
+		failed := newWorkspacePod(name, "code-server:v1", time.Hour, v1.PodFailed)
+		failed.Spec.NodeName = "node-1"
+		failed.Status.Reason = "Evicted"
+		c := newFakeClient(runningPVC(""), failed)
+		newHealer(c).Heal(context.Background())
+
+		pod := getPod(c, name)
+		Expect(pod).NotTo(BeNil())
+		Expect(pod.Status.Phase).NotTo(Equal(v1.PodFailed))
+		Expect(pod.Spec.NodeName).To(BeEmpty())
+
+		history := workspace.RestartHistory(getPVC(c, name))
+		Expect(history).To(HaveLen(1))
+		Expect(history[0].Reason).To(Equal("Evicted"))
+		Expect(history[0].NodeName).To(Equal("node-1"))
+	})
+
+	DescribeTable("waits for the backoff after recent restarts",
+		func(history string, recreated bool) {
+			c := newFakeClient(runningPVC(history))
+			newHealer(c).Heal(context.Background())
+
+			if recreated {
+				Expect(getPod(c, name)).NotTo(BeNil())
+			} else {
+				Expect(getPod(c, name)).To(BeNil())
+				Expect(recorder.Events).To(BeEmpty())
+			}
+		},
+		Entry("one restart within the initial backoff", restartHistory(time.Second*30), false),
+		Entry("one restart after the initial backoff", restartHistory(time.Minute*2), true),
+		Entry("two restarts within the doubled backoff", restartHistory(time.Minute*10, time.Minute), false),
+		Entry("two restarts after the doubled backoff", restartHistory(time.Minute*10, time.Minute*3), true),
+		Entry("restarts outside the window are ignored", restartHistory(time.Hour*3, time.Hour*2), true),
+	)
+
+	It("gives up and records the workspace as stopped after MaxRestarts", func() {
+		failed := newWorkspacePod(name, "code-server:v1", time.Hour, v1.PodFailed)
+		c := newFakeClient(runningPVC(restartHistory(time.Minute*30, time.Minute*20, time.Minute*10)), failed)
+		newHealer(c).Heal(context.Background())
+
+		Expect(getPVC(c, name).Annotations[workspace.AnnotationDesiredState]).To(Equal(workspace.DesiredStopped))
+		// 失败的Pod保留用于排查
+		pod := getPod(c, name)
+		Expect(pod).NotTo(BeNil())
+		Expect(pod.Status.Phase).To(Equal(v1.PodFailed))
+		Expect(recorder.Events).To(Receive(ContainSubstring(events.ReasonHealGaveUp)))
+	})
+
+	DescribeTable("ignores workspaces that should not be healed",
+		func(annotations map[string]string, pod *v1.Pod) {
+			objs := []client.Object{newWorkspacePVC(name, time.Hour, annotations)}
+			if pod != nil {
+				objs = append(objs, pod)
+			}
+			c := newFakeClient(objs...)
+			newHealer(c).Heal(context.Background())
+
+			Expect(recorder.Events).To(BeEmpty())
+			if pod == nil {
+				Expect(getPod(c, name)).To(BeNil())
+			}
+		},
+		Entry("desired stopped", map[string]string{
+			workspace.AnnotationDesiredState: workspace.DesiredStopped,
+			workspace.AnnotationPodTemplate:  templateOf(newWorkspacePod(name, "code-server:v1", 0, "")),
+		}, nil),
+		Entry("no saved template", map[string]string{
+			workspace.AnnotationDesiredState: workspace.DesiredRunning,
+		}, nil),
+		Entry("running pod", map[string]string{
+			workspace.AnnotationDesiredState: workspace.DesiredRunning,
+			workspace.AnnotationPodTemplate:  templateOf(newWorkspacePod(name, "code-server:v1", 0, "")),
+		}, newWorkspacePod(name, "code-server:v1", time.Hour, v1.PodRunning)),
+	)
+})
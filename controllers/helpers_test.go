@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/workspace"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testNamespace = "cloud-ide"
+
+func newFakeClient(objs ...client.Object) client.Client {
+	return fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objs...).Build()
+}
+
+// newWorkspacePVC 工作空间的PVC,创建时间为age之前
+func newWorkspacePVC(name string, age time.Duration, annotations map[string]string) *v1.PersistentVolumeClaim {
+	return &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         testNamespace,
+			Labels:            map[string]string{workspace.LabelKind: workspace.KindCloudIde},
+			Annotations:       annotations,
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+		},
+	}
+}
+
+// newWorkspacePod 挂载同名PVC的工作空间Pod,创建时间为age之前
+func newWorkspacePod(name, image string, age time.Duration, phase v1.PodPhase) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+			Labels: map[string]string{
+				workspace.LabelKind:      workspace.KindCloudIde,
+				workspace.LabelWorkspace: name,
+			},
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{Name: name, Image: image}},
+			Volumes: []v1.Volume{{
+				Name: "volume",
+				VolumeSource: v1.VolumeSource{
+					PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: name},
+				},
+			}},
+		},
+		Status: v1.PodStatus{Phase: phase},
+	}
+}
+
+// templateOf PVC上保存的Pod模板
+func templateOf(pod *v1.Pod) string {
+	data, err := json.Marshal(workspace.PodTemplate(pod))
+	Expect(err).NotTo(HaveOccurred())
+	return string(data)
+}
+
+// restartHistory 最近的重启记录,ago为每次重启距现在的时间
+func restartHistory(ago ...time.Duration) string {
+	var records []workspace.RestartRecord
+	for _, d := range ago {
+		records = append(records, workspace.RestartRecord{Time: metav1.NewTime(time.Now().Add(-d)), Reason: "Failed"})
+	}
+	data, err := json.Marshal(records)
+	Expect(err).NotTo(HaveOccurred())
+	return string(data)
+}
+
+func getPVC(c client.Client, name string) *v1.PersistentVolumeClaim {
+	pvc := &v1.PersistentVolumeClaim{}
+	Expect(c.Get(context.Background(), client.ObjectKey{Namespace: testNamespace, Name: name}, pvc)).To(Succeed())
+	return pvc
+}
+
+// getPod Pod不存在时返回nil
+func getPod(c client.Client, name string) *v1.Pod {
+	pod := &v1.Pod{}
+	err := c.Get(context.Background(), client.ObjectKey{Namespace: testNamespace, Name: name}, pod)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	Expect(err).NotTo(HaveOccurred())
+	return pod
+}
@@ -18,15 +18,23 @@ package controllers
 
 import (
 	"context"
-	"fmt"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/devcontainer"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/events"
 	"github.com/mangohow/cloud-ide-k8s-controller/tools/statussync"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/webhook"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/workspace"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
 
 // PodReconciler reconciles a Pod object
@@ -34,15 +42,31 @@ type PodReconciler struct {
 	client.Client
 	Scheme         *runtime.Scheme
 	statusInformer *statussync.StatusInformer
+	recorder       record.EventRecorder
+	notifier       *webhook.Notifier
+
+	// 记录上一次观察到的Pod状态,用于发现状态变化,避免每次调谐都重复通知
+	states *podStates
 }
 
-func NewPodReconciler(client client.Client, scheme *runtime.Scheme, statusSyncManager *statussync.StatusInformer) *PodReconciler {
-	return &PodReconciler{Client: client, Scheme: scheme, statusInformer: statusSyncManager}
+func NewPodReconciler(client client.Client, scheme *runtime.Scheme, statusSyncManager *statussync.StatusInformer,
+	recorder record.EventRecorder, notifier *webhook.Notifier) *PodReconciler {
+	return &PodReconciler{
+		Client:         client,
+		Scheme:         scheme,
+		statusInformer: statusSyncManager,
+		recorder:       recorder,
+		notifier:       notifier,
+		states:         newPodStates(),
+	}
 }
 
 //+kubebuilder:rbac:groups=cloud-ide.my.domain,resources=pods,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=cloud-ide.my.domain,resources=pods/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=cloud-ide.my.domain,resources=pods/finalizers,verbs=update
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -65,19 +89,287 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 			return ctrl.Result{Requeue: true}, err
 		}
 
+		// Pod已经被删除,通知工作空间已停止
+		if st, ok := r.states.forget(req.NamespacedName); ok {
+			reason := st.stopReason
+			if reason == "" {
+				reason = "Deleted"
+			}
+			r.notifier.Notify(webhook.Event{
+				Type:      webhook.EventWorkspaceStopped,
+				Workspace: st.workspace,
+				Namespace: req.Namespace,
+				NodeName:  st.nodeName,
+				Reason:    reason,
+				Expected:  st.expected,
+			})
+			r.recordNotified(ctx, req.Namespace, st.claim, workspace.NotifiedState{State: workspace.NotifiedStopped, NodeName: st.nodeName})
+		}
 		return ctrl.Result{}, nil
 	}
-	fmt.Printf("name:%s, status:%s\n", pod.Name, pod.Status.Phase)
-	// 通知对端Pod已经处于Running状态
-	if pod.Status.Phase == v1.PodRunning {
-		r.statusInformer.Sync(pod.Name)
+	// 只处理工作空间的Pod,预热池等其它Pod忽略
+	if pod.Labels[workspace.LabelKind] != workspace.KindCloudIde {
+		return ctrl.Result{}, nil
+	}
+	logger.V(1).Info("reconcile pod", "status", pod.Status.Phase)
+	// 通知对端Pod已经就绪,Running时code-server不一定可以访问,因此以Ready条件为准
+	retry := false
+	if workspace.IsPodReady(pod) {
+		r.statusInformer.Sync(workspace.Name(pod))
+		retry = r.recordRunning(ctx, pod)
+	}
+	r.recordGitClone(ctx, pod)
+
+	// 第一次观察到的Pod(例如控制器重启后)根据PVC上记录的已通知状态判断是否已经通知过
+	var notified *workspace.NotifiedState
+	if !r.states.known(req.NamespacedName, pod.UID) {
+		notified = r.notifiedState(ctx, pod)
+	}
+	t := r.states.observe(req.NamespacedName, pod, notified)
+	if t.becameRunning {
+		r.recorder.Eventf(pod, v1.EventTypeNormal, events.ReasonPodReady, "pod is ready on node %s", pod.Spec.NodeName)
+		r.notify(pod, webhook.EventWorkspaceRunning, "", true)
+		r.recordNotified(ctx, pod.Namespace, workspace.ClaimName(pod), workspace.NotifiedState{
+			State:    workspace.NotifiedRunning,
+			PodUID:   pod.UID,
+			NodeName: pod.Spec.NodeName,
+		})
+		// 标记PVC已经使用过,垃圾回收时不会将其当作创建失败遗留的PVC
+		if claim := workspace.ClaimName(pod); claim != "" {
+			if err := workspace.MarkInitialized(ctx, r.Client, pod.Namespace, claim); err != nil && !errors.IsNotFound(err) {
+				logger.Error(err, "mark pvc initialized", "pvc", claim)
+			}
+		}
+	}
+	if t.restarted {
+		r.notify(pod, webhook.EventWorkspaceRestarted, t.reason, false)
+	}
+	if t.becameFailed {
+		r.notify(pod, webhook.EventWorkspaceFailed, t.reason, false)
 	}
 
+	res, err := r.reconcileStarting(ctx, pod)
+	if retry && err == nil && !res.Requeue && res.RequeueAfter == 0 {
+		res.RequeueAfter = time.Second
+	}
+
+	return res, err
+}
+
+// reconcileStarting 处理正在启动的Pod:就绪后删除操作注解,超过截止时间仍未就绪则删除Pod,
+// 控制器启动时会对所有Pod调谐一次,因此重启前未完成的启动操作也会在这里继续处理
+func (r *PodReconciler) reconcileStarting(ctx context.Context, pod *v1.Pod) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	deadline, ok := workspace.StartDeadline(pod)
+	if !ok || pod.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+
+	if workspace.IsPodReady(pod) {
+		if err := workspace.FinishOperation(ctx, r.Client, pod); err != nil && !errors.IsNotFound(err) {
+			logger.Error(err, "finish starting operation")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if wait := time.Until(deadline); wait > 0 {
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+
+	// 超时,Pod启动失败,可能是由于资源不足,将Pod删除
+	logger.Info("pod start timeout, delete it", "deadline", deadline)
+	r.recorder.Event(pod, v1.EventTypeWarning, events.ReasonStartTimeout, "pod did not become ready before the start deadline, deleting it")
+	if err := workspace.DeletePod(ctx, r.Client, pod, workspace.StopReasonStartTimeout); err != nil && !errors.IsNotFound(err) {
+		r.recorder.Eventf(pod, v1.EventTypeWarning, events.ReasonDeletePodFailed, "delete pod failed: %v", err)
+		return ctrl.Result{}, err
+	}
+	r.recorder.Event(pod, v1.EventTypeNormal, events.ReasonPodDeleted, "deleted pod")
+
 	return ctrl.Result{}, nil
 }
 
+// recordRunning 保存就绪的Pod的模板,Pod意外消失后由自动恢复按模板重新创建。期望状态由后端启动和停止时记录。
+// PVC刚刚被修改(例如工作空间被停止)导致冲突时返回true,稍后重新调谐
+func (r *PodReconciler) recordRunning(ctx context.Context, pod *v1.Pod) bool {
+	// 控制器正在停止的Pod,期望状态已经记录为停止
+	if pod.DeletionTimestamp != nil || pod.Annotations[workspace.AnnotationStopReason] != "" {
+		return false
+	}
+	claim := workspace.ClaimName(pod)
+	if claim == "" {
+		return false
+	}
+	pvc := &v1.PersistentVolumeClaim{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: pod.Namespace, Name: claim}, pvc); err != nil {
+		if !errors.IsNotFound(err) {
+			log.FromContext(ctx).Error(err, "get pvc", "pvc", claim)
+		}
+		return false
+	}
+	if pvc.DeletionTimestamp != nil || pvc.Labels[workspace.LabelState] == workspace.StateTrashed {
+		return false
+	}
+	err := workspace.RecordTemplate(ctx, r.Client, pvc, pod)
+	if errors.IsConflict(err) {
+		return true
+	}
+	if err != nil && !errors.IsNotFound(err) {
+		log.FromContext(ctx).Error(err, "record pod template", "pvc", claim)
+	}
+
+	return false
+}
+
+// recordGitClone 在PVC上记录git-clone init容器的克隆结果,克隆成功后的启动不再克隆
+func (r *PodReconciler) recordGitClone(ctx context.Context, pod *v1.Pod) {
+	cond, done := workspace.GitCloneResult(pod)
+	claim := workspace.ClaimName(pod)
+	if !done || claim == "" {
+		return
+	}
+	pvc := &v1.PersistentVolumeClaim{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: pod.Namespace, Name: claim}, pvc); err != nil {
+		if !errors.IsNotFound(err) {
+			log.FromContext(ctx).Error(err, "get pvc", "pvc", claim)
+		}
+		return
+	}
+	changed, err := workspace.SetGitCondition(ctx, r.Client, pvc, cond)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			log.FromContext(ctx).Error(err, "record git condition", "pvc", claim)
+		}
+		return
+	}
+	if changed {
+		if cond.Status == metav1.ConditionTrue {
+			r.recorder.Event(pvc, v1.EventTypeNormal, events.ReasonGitCloned, cond.Message)
+		} else {
+			r.recorder.Event(pvc, v1.EventTypeWarning, events.ReasonGitCloneFailed, cond.Message)
+		}
+	}
+	r.recordDevcontainer(ctx, pod, pvc)
+}
+
+// recordDevcontainer 保存从仓库中读取的devcontainer.json,下次启动时生效
+func (r *PodReconciler) recordDevcontainer(ctx context.Context, pod *v1.Pod, pvc *v1.PersistentVolumeClaim) {
+	p := pvc.Annotations[workspace.AnnotationDevcontainerPath]
+	if p == "" || pvc.Annotations[workspace.AnnotationDevcontainer] != "" {
+		return
+	}
+	data := workspace.ClonedDevcontainer(pod)
+	if data == "" {
+		return
+	}
+	data, err := devcontainer.Compact([]byte(data))
+	if err != nil {
+		r.recorder.Eventf(pvc, v1.EventTypeWarning, events.ReasonDevcontainerInvalid, "%s: %v", p, err)
+		return
+	}
+	if err := workspace.SetDevcontainer(ctx, r.Client, pvc, data); err != nil {
+		if !errors.IsNotFound(err) {
+			log.FromContext(ctx).Error(err, "record devcontainer", "pvc", pvc.Name)
+		}
+		return
+	}
+	r.recorder.Eventf(pvc, v1.EventTypeNormal, events.ReasonDevcontainerLoaded, "loaded %s from the repository, applied from the next start", p)
+}
+
+// notifiedState 返回Pod的PVC上记录的已通知状态,没有记录时返回nil
+func (r *PodReconciler) notifiedState(ctx context.Context, pod *v1.Pod) *workspace.NotifiedState {
+	claim := workspace.ClaimName(pod)
+	if claim == "" {
+		return nil
+	}
+	pvc := &v1.PersistentVolumeClaim{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: pod.Namespace, Name: claim}, pvc); err != nil {
+		if !errors.IsNotFound(err) {
+			log.FromContext(ctx).Error(err, "get pvc", "pvc", claim)
+		}
+		return nil
+	}
+	st, ok := workspace.GetNotifiedState(pvc)
+	if !ok {
+		return nil
+	}
+
+	return &st
+}
+
+// recordNotified 在PVC上记录已通知的状态,控制器重启后据此判断状态变化
+func (r *PodReconciler) recordNotified(ctx context.Context, namespace, claim string, st workspace.NotifiedState) {
+	if claim == "" {
+		return
+	}
+	if err := workspace.SetNotifiedState(ctx, r.Client, namespace, claim, st); err != nil && !errors.IsNotFound(err) {
+		log.FromContext(ctx).Error(err, "record notified state", "pvc", claim)
+	}
+}
+
+// notifyLost 控制器启动时补发停止通知: 已通知Running的Pod在控制器停止期间消失,不会再触发调谐
+func (r *PodReconciler) notifyLost(ctx context.Context) error {
+	logger := log.FromContext(ctx)
+	pvcs := &v1.PersistentVolumeClaimList{}
+	if err := r.Client.List(ctx, pvcs); err != nil {
+		logger.Error(err, "list pvc")
+		return nil
+	}
+	pods := &v1.PodList{}
+	if err := r.Client.List(ctx, pods, client.MatchingLabels{workspace.LabelKind: workspace.KindCloudIde}); err != nil {
+		logger.Error(err, "list pod")
+		return nil
+	}
+	alive := make(map[types.UID]bool, len(pods.Items))
+	for i := range pods.Items {
+		alive[pods.Items[i].UID] = true
+	}
+
+	for i := range pvcs.Items {
+		pvc := &pvcs.Items[i]
+		st, ok := workspace.GetNotifiedState(pvc)
+		if !ok || st.State != workspace.NotifiedRunning || alive[st.PodUID] {
+			continue
+		}
+		// 先记录再通知,调谐时已经通知过停止的PVC会返回冲突,避免重复通知
+		if err := workspace.MarkNotifiedStopped(ctx, r.Client, pvc, st.NodeName); err != nil {
+			if !errors.IsConflict(err) && !errors.IsNotFound(err) {
+				logger.Error(err, "record notified state", "pvc", pvc.Name)
+			}
+			continue
+		}
+		r.notifier.Notify(webhook.Event{
+			Type:      webhook.EventWorkspaceStopped,
+			Workspace: pvc.Name,
+			Namespace: pvc.Namespace,
+			NodeName:  st.NodeName,
+			Reason:    "Deleted",
+			Expected:  pvc.Annotations[workspace.AnnotationDesiredState] == workspace.DesiredStopped,
+		})
+	}
+
+	return nil
+}
+
+func (r *PodReconciler) notify(pod *v1.Pod, eventType, reason string, expected bool) {
+	r.notifier.Notify(webhook.Event{
+		Type:      eventType,
+		Workspace: workspace.Name(pod),
+		Namespace: pod.Namespace,
+		NodeName:  pod.Spec.NodeName,
+		Phase:     string(pod.Status.Phase),
+		Reason:    reason,
+		Expected:  expected,
+		Message:   pod.Status.Message,
+	})
+}
+
 // SetupWithManager sets up the controller with the StatusInformer.
 func (r *PodReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.Add(manager.RunnableFunc(r.notifyLost)); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		// Uncomment the following line adding a pointer to an instance of the controlled resource as an argument
 		For(&v1.Pod{}).
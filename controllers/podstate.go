@@ -0,0 +1,163 @@
+package controllers
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/workspace"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// podState 上一次调谐时观察到的Pod状态,用于判断Pod的状态变化
+type podState struct {
+	uid types.UID
+	// Pod所属的工作空间,StatefulSet创建的Pod名称与工作空间不同
+	workspace string
+	// Pod使用的PVC,Pod删除后在PVC上记录已通知停止
+	claim    string
+	nodeName string
+	// Pod已经就绪过(code-server可以访问)
+	running  bool
+	failed   bool
+	restarts int32
+	// Pod被删除的原因,Pod删除后发送通知时使用
+	stopReason string
+	expected   bool
+}
+
+// transition 一次调谐中Pod发生的状态变化
+type transition struct {
+	becameRunning bool
+	becameFailed  bool
+	restarted     bool
+	// 容器重启或Pod失败的原因
+	reason string
+}
+
+type podStates struct {
+	sync.Mutex
+	m map[types.NamespacedName]*podState
+	// 控制器启动时间,PVC上没有已通知状态时,启动前就已经Running的Pod不再重复通知
+	since time.Time
+}
+
+func newPodStates() *podStates {
+	return &podStates{m: make(map[types.NamespacedName]*podState), since: time.Now()}
+}
+
+// known 是否已经观察过该Pod
+func (s *podStates) known(key types.NamespacedName, uid types.UID) bool {
+	s.Lock()
+	defer s.Unlock()
+	st, ok := s.m[key]
+
+	return ok && st.uid == uid
+}
+
+// observe 记录Pod当前的状态,并返回与上一次相比发生的变化。
+// notified为PVC上记录的已通知状态,第一次观察到该Pod时用于判断是否已经通知过Running
+func (s *podStates) observe(key types.NamespacedName, pod *v1.Pod, notified *workspace.NotifiedState) transition {
+	s.Lock()
+	defer s.Unlock()
+	st, ok := s.m[key]
+	if !ok || st.uid != pod.UID {
+		st = &podState{uid: pod.UID, restarts: -1}
+		if notified != nil {
+			st.running = notified.State == workspace.NotifiedRunning && notified.PodUID == pod.UID
+		} else if !ok && workspace.IsPodReady(pod) && pod.Status.StartTime != nil && pod.Status.StartTime.Time.Before(s.since) {
+			// 旧版本的PVC没有已通知状态,控制器启动前就已经Running的Pod认为已经通知过
+			st.running = true
+		}
+		s.m[key] = st
+	}
+	st.workspace = workspace.Name(pod)
+	st.claim = workspace.ClaimName(pod)
+	st.nodeName = pod.Spec.NodeName
+
+	var t transition
+	if workspace.IsPodReady(pod) && !st.running {
+		st.running = true
+		t.becameRunning = true
+	}
+
+	restarts, lastReason := containerRestarts(pod)
+	// restarts为-1表示第一次观察到该Pod,此时不认为发生了重启
+	if st.restarts >= 0 && restarts > st.restarts {
+		t.restarted = true
+		t.reason = lastReason
+	}
+	st.restarts = restarts
+
+	if pod.Status.Phase == v1.PodFailed && !st.failed {
+		st.failed = true
+		t.becameFailed = true
+		t.reason = failedReason(pod)
+	}
+
+	if pod.DeletionTimestamp != nil && st.stopReason == "" {
+		if reason := pod.Annotations[workspace.AnnotationStopReason]; reason != "" {
+			st.stopReason = reason
+			st.expected = true
+		} else {
+			st.stopReason = deletedReason(pod)
+		}
+	}
+
+	return t
+}
+
+// forget Pod已经被删除,返回最后一次观察到的状态
+func (s *podStates) forget(key types.NamespacedName) (podState, bool) {
+	s.Lock()
+	defer s.Unlock()
+	st, ok := s.m[key]
+	if !ok {
+		return podState{}, false
+	}
+	delete(s.m, key)
+
+	return *st, true
+}
+
+// containerRestarts 返回所有容器的重启次数之和以及最近一次终止的原因(例如OOMKilled)
+func containerRestarts(pod *v1.Pod) (int32, string) {
+	var total int32
+	var reason string
+	for _, cs := range pod.Status.ContainerStatuses {
+		total += cs.RestartCount
+		if cs.LastTerminationState.Terminated != nil {
+			reason = cs.LastTerminationState.Terminated.Reason
+		}
+	}
+
+	return total, reason
+}
+
+func failedReason(pod *v1.Pod) string {
+	// Evicted等由kubelet设置的原因
+	if pod.Status.Reason != "" {
+		return pod.Status.Reason
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Terminated != nil && cs.State.Terminated.Reason != "" {
+			return cs.State.Terminated.Reason
+		}
+	}
+
+	return "Failed"
+}
+
+func deletedReason(pod *v1.Pod) string {
+	// 节点失联后Pod的Ready状态会变为Unknown,随后被节点控制器驱逐
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == v1.PodReady && cond.Status == v1.ConditionUnknown {
+			return "NodeLost"
+		}
+	}
+	if pod.Status.Reason != "" {
+		return pod.Status.Reason
+	}
+
+	return "Deleted"
+}
@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/events"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/workspace"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// PVCReconciler 工作空间的PVC调谐器,PVC即工作空间,PVC删除时按顺序清理工作空间的所有资源
+type PVCReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	recorder record.EventRecorder
+}
+
+func NewPVCReconciler(client client.Client, scheme *runtime.Scheme, recorder record.EventRecorder) *PVCReconciler {
+	return &PVCReconciler{Client: client, Scheme: scheme, recorder: recorder}
+}
+
+//+kubebuilder:rbac:groups="",resources=persistentvolumeclaims/finalizers,verbs=update
+
+// Reconcile 为工作空间的PVC添加finalizer,PVC删除时先删除Pod,再删除附属资源,最后移除finalizer
+func (r *PVCReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	pvc := &v1.PersistentVolumeClaim{}
+	err := r.Client.Get(ctx, req.NamespacedName, pvc)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			logger.Error(err, "get pvc")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if pvc.DeletionTimestamp == nil {
+		if pvc.Labels[workspace.LabelKind] != workspace.KindCloudIde || controllerutil.ContainsFinalizer(pvc, workspace.FinalizerCleanup) {
+			return ctrl.Result{}, nil
+		}
+		controllerutil.AddFinalizer(pvc, workspace.FinalizerCleanup)
+		return ctrl.Result{}, r.Client.Update(ctx, pvc)
+	}
+
+	if !controllerutil.ContainsFinalizer(pvc, workspace.FinalizerCleanup) {
+		return ctrl.Result{}, nil
+	}
+	stage, err := workspace.Cleanup(ctx, r.Client, pvc.Namespace, pvc.Name)
+	if err != nil {
+		logger.Error(err, "cleanup workspace")
+		return ctrl.Result{}, err
+	}
+	if stage != workspace.StageReleasingPVC {
+		logger.Info("cleanup workspace", "stage", stage)
+		return ctrl.Result{RequeueAfter: time.Second * 2}, nil
+	}
+
+	controllerutil.RemoveFinalizer(pvc, workspace.FinalizerCleanup)
+	if err := r.Client.Update(ctx, pvc); err != nil && !errors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	r.recorder.Event(pvc, v1.EventTypeNormal, events.ReasonPVCDeleted, "workspace resources cleaned up, releasing pvc")
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *PVCReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1.PersistentVolumeClaim{}).
+		Complete(r)
+}
@@ -0,0 +1,36 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/backend"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/workspace"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// restartWorkspace 停止工作空间,等待旧Pod删除完成后以tpl重新启动,新Pod的启动截止时间由PodReconciler处理
+func restartWorkspace(ctx context.Context, b backend.WorkspaceBackend, pvc *v1.PersistentVolumeClaim, tpl *v1.Pod,
+	reason string, startTimeout time.Duration) error {
+	workspace.SetOwner(tpl, pvc)
+	workspace.SetStarting(tpl, time.Now().Add(startTimeout))
+
+	if err := b.Stop(ctx, pvc.Namespace, pvc.Name, reason); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	err := wait.PollImmediateWithContext(ctx, time.Second, time.Minute*2, func(ctx context.Context) (bool, error) {
+		_, err := b.Get(ctx, pvc.Namespace, pvc.Name)
+		if errors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, client.IgnoreNotFound(err)
+	})
+	if err != nil {
+		return err
+	}
+
+	return b.Start(ctx, tpl)
+}
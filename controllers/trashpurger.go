@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/conf"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/events"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/workspace"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// TrashPurger 定期彻底删除回收站中超过保留期的工作空间,实现了manager.Runnable,只在leader上运行
+type TrashPurger struct {
+	client   client.Client
+	recorder record.EventRecorder
+	cfg      conf.TrashConfig
+}
+
+func NewTrashPurger(client client.Client, recorder record.EventRecorder, cfg conf.TrashConfig) *TrashPurger {
+	return &TrashPurger{client: client, recorder: recorder, cfg: cfg}
+}
+
+func (p *TrashPurger) Start(ctx context.Context) error {
+	ticker := time.NewTicker(p.cfg.PurgeInterval.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			p.Purge(ctx)
+		}
+	}
+}
+
+// Purge 删除所有超过保留期的工作空间,PVC删除后由PVCReconciler清理其它资源
+func (p *TrashPurger) Purge(ctx context.Context) {
+	logger := log.FromContext(ctx).WithName("trash")
+	pvcs := &v1.PersistentVolumeClaimList{}
+	if err := p.client.List(ctx, pvcs, client.MatchingLabels{workspace.LabelState: workspace.StateTrashed}); err != nil {
+		logger.Error(err, "list trashed pvc")
+		return
+	}
+
+	now := time.Now()
+	for i := range pvcs.Items {
+		pvc := &pvcs.Items[i]
+		if pvc.DeletionTimestamp != nil {
+			continue
+		}
+		purgeAt, err := time.Parse(time.RFC3339, pvc.Annotations[workspace.AnnotationPurgeAt])
+		if err == nil && now.Before(purgeAt) {
+			continue
+		}
+
+		if err := workspace.DeletePVC(ctx, p.client, pvc); err != nil && !errors.IsNotFound(err) {
+			logger.Error(err, "purge workspace", "pvc", client.ObjectKeyFromObject(pvc))
+			p.recorder.Eventf(pvc, v1.EventTypeWarning, events.ReasonDeletePVCFailed, "purge workspace failed: %v", err)
+			continue
+		}
+		logger.Info("workspace purged", "pvc", client.ObjectKeyFromObject(pvc))
+		p.recorder.Event(pvc, v1.EventTypeNormal, events.ReasonWorkspacePurged, "retention period expired, workspace deleted")
+	}
+}
@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/conf"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/events"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/workspace"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+var _ = Describe("TrashPurger", func() {
+	// trashedPVC 回收站中的工作空间,purgeAt之后彻底删除
+	trashedPVC := func(name string, purgeAt time.Time) *v1.PersistentVolumeClaim {
+		pvc := newWorkspacePVC(name, time.Hour, map[string]string{
+			workspace.AnnotationPurgeAt: purgeAt.UTC().Format(time.RFC3339),
+		})
+		pvc.Labels[workspace.LabelState] = workspace.StateTrashed
+		return pvc
+	}
+
+	DescribeTable("purges workspaces after the retention period",
+		func(purgeAt time.Duration, trashed, purged bool) {
+			pvc := trashedPVC("ws-1", time.Now().Add(purgeAt))
+			if !trashed {
+				delete(pvc.Labels, workspace.LabelState)
+			}
+			c := newFakeClient(pvc)
+			recorder := record.NewFakeRecorder(10)
+			NewTrashPurger(c, recorder, conf.TrashConfig{}).Purge(context.Background())
+
+			// PVC带有finalizer,删除后由PVCReconciler清理其它资源
+			pvc = getPVC(c, "ws-1")
+			if purged {
+				Expect(pvc.DeletionTimestamp).NotTo(BeNil())
+				Expect(controllerutil.ContainsFinalizer(pvc, workspace.FinalizerCleanup)).To(BeTrue())
+				Expect(recorder.Events).To(Receive(ContainSubstring(events.ReasonWorkspacePurged)))
+			} else {
+				Expect(pvc.DeletionTimestamp).To(BeNil())
+				Expect(recorder.Events).To(BeEmpty())
+			}
+		},
+		Entry("retention expired", -time.Minute, true, true),
+		Entry("within retention", time.Hour, true, false),
+		Entry("not in the trash", -time.Minute, false, false),
+	)
+})
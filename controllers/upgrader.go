@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/conf"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/backend"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/events"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/workspace"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+/*
+	镜像升级: UpgradeSpaces在PVC上记录升级后的镜像,停止的工作空间在下次启动时使用新镜像,
+	运行中的工作空间由Upgrader在维护窗口内逐批重启: 停止工作空间,等待旧Pod删除完成后用新镜像重新启动
+*/
+
+// Upgrader 在维护窗口内重启需要升级镜像的工作空间,实现了manager.Runnable,只在leader上运行
+type Upgrader struct {
+	client       client.Client
+	backend      backend.WorkspaceBackend
+	recorder     record.EventRecorder
+	cfg          conf.UpgradeConfig
+	startTimeout time.Duration
+
+	location    *time.Location
+	startOffset time.Duration
+}
+
+func NewUpgrader(client client.Client, backend backend.WorkspaceBackend, recorder record.EventRecorder, cfg conf.UpgradeConfig, startTimeout time.Duration) (*Upgrader, error) {
+	location, err := time.LoadLocation(cfg.Window.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid maintenance window timezone %q: %v", cfg.Window.Timezone, err)
+	}
+	start, err := time.Parse("15:04", cfg.Window.Start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid maintenance window start %q: %v", cfg.Window.Start, err)
+	}
+
+	return &Upgrader{
+		client:       client,
+		backend:      backend,
+		recorder:     recorder,
+		cfg:          cfg,
+		startTimeout: startTimeout,
+		location:     location,
+		startOffset:  time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute,
+	}, nil
+}
+
+func (u *Upgrader) Start(ctx context.Context) error {
+	ticker := time.NewTicker(u.cfg.CheckInterval.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if u.InWindow(time.Now()) {
+				u.Upgrade(ctx)
+			}
+		}
+	}
+}
+
+// InWindow 判断当前是否在维护窗口内,窗口可以跨越零点
+func (u *Upgrader) InWindow(now time.Time) bool {
+	now = now.In(u.location)
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, u.location).Add(u.startOffset)
+	if now.Before(start) {
+		start = start.AddDate(0, 0, -1)
+	}
+
+	return now.Sub(start) < u.cfg.Window.Duration.Duration
+}
+
+// Upgrade 重启镜像与升级目标不一致的运行中工作空间,每次最多重启MaxRestarts个
+func (u *Upgrader) Upgrade(ctx context.Context) {
+	logger := log.FromContext(ctx).WithName("upgrade")
+	pvcs := &v1.PersistentVolumeClaimList{}
+	if err := u.client.List(ctx, pvcs, client.MatchingLabels{workspace.LabelKind: workspace.KindCloudIde}); err != nil {
+		logger.Error(err, "list pvc")
+		return
+	}
+
+	restarted := 0
+	for i := range pvcs.Items {
+		if restarted >= u.cfg.MaxRestarts {
+			return
+		}
+		pvc := &pvcs.Items[i]
+		image := pvc.Annotations[workspace.AnnotationImage]
+		if image == "" || pvc.DeletionTimestamp != nil {
+			continue
+		}
+
+		pod, err := u.backend.Get(ctx, pvc.Namespace, pvc.Name)
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				logger.Error(err, "get pod", "workspace", pvc.Name)
+			}
+			continue
+		}
+		// 正在启动或停止的工作空间下次检查时再处理
+		if _, starting := workspace.StartDeadline(pod); starting || pod.DeletionTimestamp != nil {
+			continue
+		}
+		if c := workspace.Container(pod); c == nil || c.Image == image {
+			continue
+		}
+
+		restarted++
+		if err := u.restart(ctx, pvc, pod, image); err != nil {
+			logger.Error(err, "restart workspace", "workspace", pvc.Name)
+			u.recorder.Eventf(pvc, v1.EventTypeWarning, events.ReasonCreatePodFailed, "restart with image %s failed: %v", image, err)
+			continue
+		}
+		logger.Info("workspace upgraded", "workspace", pvc.Name, "image", image)
+		u.recorder.Eventf(pvc, v1.EventTypeNormal, events.ReasonWorkspaceUpgraded, "restarted with image %s", image)
+	}
+}
+
+// restart 停止工作空间并用新镜像重新启动,新Pod的启动截止时间由PodReconciler处理
+func (u *Upgrader) restart(ctx context.Context, pvc *v1.PersistentVolumeClaim, pod *v1.Pod, image string) error {
+	newPod := workspace.PodTemplate(pod)
+	workspace.Container(newPod).Image = image
+
+	return restartWorkspace(ctx, u.backend, pvc, newPod, workspace.StopReasonUpgrade, u.startTimeout)
+}
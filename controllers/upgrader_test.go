@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/conf"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/backend"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/events"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/workspace"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Upgrader", func() {
+	var recorder *record.FakeRecorder
+
+	BeforeEach(func() {
+		recorder = record.NewFakeRecorder(10)
+	})
+
+	newUpgrader := func(c client.Client, maxRestarts int) *Upgrader {
+		u, err := NewUpgrader(c, backend.NewPodBackend(c), recorder, conf.UpgradeConfig{
+			Window: conf.MaintenanceWindow{
+				Start:    "22:00",
+				Duration: metav1.Duration{Duration: time.Hour * 4},
+				Timezone: "Asia/Shanghai",
+			},
+			MaxRestarts: maxRestarts,
+		}, time.Minute)
+		Expect(err).NotTo(HaveOccurred())
+		return u
+	}
+
+	// upgradingPVC 升级目标为image的工作空间
+	upgradingPVC := func(name, image string) *v1.PersistentVolumeClaim {
+		return newWorkspacePVC(name, time.Hour, map[string]string{
+			workspace.AnnotationDesiredState: workspace.DesiredRunning,
+			workspace.AnnotationImage:        image,
+		})
+	}
+
+	image := func(c client.Client, name string) string {
+		pod := getPod(c, name)
+		Expect(pod).NotTo(BeNil())
+		return workspace.Container(pod).Image
+	}
+
+	DescribeTable("maintenance window",
+		func(now string, expected bool) {
+			t, err := time.Parse(time.RFC3339, now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(newUpgrader(newFakeClient(), 1).InWindow(t)).To(Equal(expected))
+		},
+		Entry("before the window", "2022-10-01T21:59:00+08:00", false),
+		Entry("at the start", "2022-10-01T22:00:00+08:00", true),
+		Entry("after midnight", "2022-10-02T01:30:00+08:00", true),
+		Entry("after the window", "2022-10-02T02:00:00+08:00", false),
+		Entry("in another timezone", "2022-10-01T15:00:00Z", true),
+	)
+
+	It("restarts running workspaces with the new image", func() {
+		c := newFakeClient(
+			upgradingPVC("ws-1", "code-server:v2"),
+			newWorkspacePod("ws-1", "code-server:v1", time.Hour, v1.PodRunning),
+		)
+		newUpgrader(c, 5).Upgrade(context.Background())
+
+		Expect(image(c, "ws-1")).To(Equal("code-server:v2"))
+		_, starting := workspace.StartDeadline(getPod(c, "ws-1"))
+		Expect(starting).To(BeTrue())
+		Expect(getPVC(c, "ws-1").Annotations[workspace.AnnotationDesiredState]).To(Equal(workspace.DesiredRunning))
+		Expect(recorder.Events).To(Receive(ContainSubstring(events.ReasonWorkspaceUpgraded)))
+	})
+
+	It("skips workspaces that are up to date, starting or stopped", func() {
+		starting := newWorkspacePod("ws-starting", "code-server:v1", time.Hour, v1.PodPending)
+		workspace.SetStarting(starting, time.Now().Add(time.Minute))
+		c := newFakeClient(
+			upgradingPVC("ws-current", "code-server:v2"),
+			newWorkspacePod("ws-current", "code-server:v2", time.Hour, v1.PodRunning),
+			upgradingPVC("ws-starting", "code-server:v2"), starting,
+			upgradingPVC("ws-stopped", "code-server:v2"),
+			// 没有升级目标
+			newWorkspacePVC("ws-other", time.Hour, nil),
+			newWorkspacePod("ws-other", "code-server:v1", time.Hour, v1.PodRunning),
+		)
+		newUpgrader(c, 5).Upgrade(context.Background())
+
+		Expect(image(c, "ws-starting")).To(Equal("code-server:v1"))
+		Expect(image(c, "ws-other")).To(Equal("code-server:v1"))
+		Expect(getPod(c, "ws-stopped")).To(BeNil())
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("restarts at most MaxRestarts workspaces per check", func() {
+		names := []string{"ws-1", "ws-2", "ws-3"}
+		var objs []client.Object
+		for _, name := range names {
+			objs = append(objs, upgradingPVC(name, "code-server:v2"), newWorkspacePod(name, "code-server:v1", time.Hour, v1.PodRunning))
+		}
+		c := newFakeClient(objs...)
+		u := newUpgrader(c, 2)
+
+		count := func() int {
+			n := 0
+			for _, name := range names {
+				if image(c, name) == "code-server:v2" {
+					n++
+				}
+			}
+			return n
+		}
+		u.Upgrade(context.Background())
+		Expect(count()).To(Equal(2))
+		u.Upgrade(context.Background())
+		Expect(count()).To(Equal(3))
+	})
+})
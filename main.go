@@ -19,12 +19,23 @@ package main
 import (
 	"flag"
 	"fmt"
+	"github.com/mangohow/cloud-ide-k8s-controller/conf"
 	"github.com/mangohow/cloud-ide-k8s-controller/middleware"
 	"github.com/mangohow/cloud-ide-k8s-controller/pb"
 	"github.com/mangohow/cloud-ide-k8s-controller/service"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/admission"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/backend"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/capacity"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/catalog"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/podexec"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/prepull"
 	"github.com/mangohow/cloud-ide-k8s-controller/tools/signal"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/sshgateway"
 	"github.com/mangohow/cloud-ide-k8s-controller/tools/statussync"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/warmpool"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/webhook"
 	"google.golang.org/grpc"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	"net"
 	"os"
@@ -62,11 +73,13 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var configFile string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&configFile, "config", "", "The path of the controller config file(yaml), use default config if empty")
 	flag.StringVar(&service.Mode, "mode", service.ModeRelease, "The program running mode(debug or release)")
 
 	opts := zap.Options{
@@ -79,6 +92,12 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	cfg, err := conf.Load(configFile)
+	if err != nil {
+		setupLog.Error(err, "unable to load config", "file", configFile)
+		os.Exit(1)
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
 		MetricsBindAddress:     metricsAddr,
@@ -104,13 +123,142 @@ func main() {
 		os.Exit(1)
 	}
 
+	// webhook通知,由manager启动投递
+	notifier, err := webhook.NewNotifier(mgr.GetClient(), mgr.GetAPIReader(), WatchedNamespace, cfg.Webhook)
+	if err != nil {
+		setupLog.Error(err, "unable to create webhook notifier")
+		os.Exit(1)
+	}
+	if err = mgr.Add(notifier); err != nil {
+		setupLog.Error(err, "unable to add webhook notifier")
+		os.Exit(1)
+	}
+
 	manager := statussync.NewManager()
-	if err = controllers.NewPodReconciler(mgr.GetClient(), mgr.GetScheme(), manager).SetupWithManager(mgr); err != nil {
+	if err = controllers.NewPodReconciler(mgr.GetClient(), mgr.GetScheme(), manager,
+		mgr.GetEventRecorderFor("cloud-ide-controller"), notifier).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Pod")
 		os.Exit(1)
 	}
+	if err = controllers.NewPVCReconciler(mgr.GetClient(), mgr.GetScheme(),
+		mgr.GetEventRecorderFor("cloud-ide-controller")).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "PersistentVolumeClaim")
+		os.Exit(1)
+	}
 	//+kubebuilder:scaffold:builder
 
+	if cfg.Trash.Retention.Duration > 0 {
+		purger := controllers.NewTrashPurger(mgr.GetClient(), mgr.GetEventRecorderFor("cloud-ide-trash"), cfg.Trash)
+		if err = mgr.Add(purger); err != nil {
+			setupLog.Error(err, "unable to add trash purger")
+			os.Exit(1)
+		}
+	}
+
+	if cfg.GC.Enabled {
+		gc := controllers.NewGarbageCollector(mgr.GetClient(), mgr.GetEventRecorderFor("cloud-ide-gc"), cfg.GC)
+		if err = mgr.Add(gc); err != nil {
+			setupLog.Error(err, "unable to add garbage collector")
+			os.Exit(1)
+		}
+	}
+
+	var imageCatalog *catalog.Catalog
+	if cfg.Catalog.Enabled {
+		imageCatalog = catalog.NewCatalog(mgr.GetClient(), WatchedNamespace, cfg.Catalog)
+	}
+
+	// 镜像预拉取,关闭时删除之前创建的DaemonSet
+	puller := prepull.NewPuller(mgr.GetClient(), WatchedNamespace, cfg.PrePull, imageCatalog)
+	if err = mgr.Add(puller); err != nil {
+		setupLog.Error(err, "unable to add image prepuller")
+		os.Exit(1)
+	}
+
+	workspaceBackend, err := backend.New(cfg.Backend, mgr.GetClient())
+	if err != nil {
+		setupLog.Error(err, "unable to create workspace backend")
+		os.Exit(1)
+	}
+
+	// 维护窗口内重启需要升级镜像的工作空间
+	if cfg.Upgrade.Window.Duration.Duration > 0 {
+		upgrader, err := controllers.NewUpgrader(mgr.GetClient(), workspaceBackend, mgr.GetEventRecorderFor("cloud-ide-upgrade"), cfg.Upgrade, cfg.StartTimeout.Duration)
+		if err != nil {
+			setupLog.Error(err, "unable to create upgrader")
+			os.Exit(1)
+		}
+		if err = mgr.Add(upgrader); err != nil {
+			setupLog.Error(err, "unable to add upgrader")
+			os.Exit(1)
+		}
+	}
+
+	if cfg.Heal.Enabled {
+		healer := controllers.NewHealer(mgr.GetClient(), workspaceBackend, mgr.GetEventRecorderFor("cloud-ide-heal"), cfg.Heal, cfg.StartTimeout.Duration)
+		if err = mgr.Add(healer); err != nil {
+			setupLog.Error(err, "unable to add healer")
+			os.Exit(1)
+		}
+	}
+
+	// 节点被封锁后通知用户并迁移工作空间
+	if cfg.Drain.Enabled {
+		coordinator := controllers.NewDrainCoordinator(mgr.GetClient(), workspaceBackend, mgr.GetEventRecorderFor("cloud-ide-drain"),
+			notifier, cfg.Drain, cfg.StartTimeout.Duration)
+		if err = mgr.Add(coordinator); err != nil {
+			setupLog.Error(err, "unable to add drain coordinator")
+			os.Exit(1)
+		}
+	}
+
+	// CheckCapacity和准入队列共用资源占用快照
+	estimator := capacity.NewEstimator(mgr.GetClient(), mgr.GetAPIReader())
+	// 准入队列在每个副本上运行,处理本副本收到的启动请求
+	var queue *admission.Queue
+	if cfg.Admission.Enabled {
+		queue = admission.NewQueue(mgr.GetClient(), estimator, workspaceBackend, cfg.Admission, cfg.StartTimeout.Duration)
+		if err = mgr.Add(queue); err != nil {
+			setupLog.Error(err, "unable to add admission queue")
+			os.Exit(1)
+		}
+	}
+
+	var pool *warmpool.Pool
+	if cfg.WarmPool.Enabled {
+		pool, err = warmpool.NewPool(mgr.GetClient(), WatchedNamespace, cfg.WarmPool)
+		if err != nil {
+			setupLog.Error(err, "unable to create warm pool")
+			os.Exit(1)
+		}
+		if err = mgr.Add(pool); err != nil {
+			setupLog.Error(err, "unable to add warm pool")
+			os.Exit(1)
+		}
+	}
+
+	// SSH网关在每个副本上运行
+	if cfg.SSH.Enabled {
+		gateway := sshgateway.NewGateway(mgr.GetClient(), workspaceBackend, WatchedNamespace, cfg.SSH)
+		if err = mgr.Add(gateway); err != nil {
+			setupLog.Error(err, "unable to add ssh gateway")
+			os.Exit(1)
+		}
+	}
+
+	var (
+		executor      *podexec.Executor
+		authenticator *podexec.Authenticator
+	)
+	if cfg.Exec.Enabled {
+		executor, err = podexec.NewExecutor(mgr.GetConfig())
+		if err != nil {
+			setupLog.Error(err, "unable to create executor")
+			os.Exit(1)
+		}
+		authenticator = podexec.NewAuthenticator(mgr.GetClient(), WatchedNamespace, cfg.Exec.TokenSecret, cfg.Exec.Operators)
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -121,7 +269,8 @@ func main() {
 	}
 
 	// 启动grpc服务
-	grpcServer := StartGrpcServer(mgr.GetClient(), manager)
+	grpcServer := StartGrpcServer(mgr.GetClient(), mgr.GetAPIReader(), manager, mgr.GetEventRecorderFor("cloud-ide-service"), cfg,
+		workspaceBackend, pool, puller, imageCatalog, queue, executor, authenticator, estimator)
 	// 安装信号处理
 	ctx := signal.SetupSignal(func() {
 		ctrl.Log.Info("receive signal, is going to shutdown")
@@ -136,16 +285,27 @@ func main() {
 	}
 }
 
-func StartGrpcServer(client client.Client, manager *statussync.StatusInformer) *grpc.Server {
+func StartGrpcServer(client client.Client, apiReader client.Reader, manager *statussync.StatusInformer, recorder record.EventRecorder,
+	cfg *conf.Config, workspaceBackend backend.WorkspaceBackend, pool *warmpool.Pool, puller *prepull.Puller, imageCatalog *catalog.Catalog, queue *admission.Queue,
+	executor *podexec.Executor, authenticator *podexec.Authenticator, estimator *capacity.Estimator) *grpc.Server {
 	listener, err := net.Listen("tcp", ":6387")
 	if err != nil {
 		panic(fmt.Errorf("create grpc service: %v", err))
 	}
+	streamInterceptors := []grpc.StreamServerInterceptor{
+		middleware.RecoveryStreamInterceptorMiddleware(),
+		middleware.LogStreamInterceptorMiddleware(),
+	}
+	// execSpace需要认证操作人
+	if authenticator != nil {
+		streamInterceptors = append(streamInterceptors,
+			middleware.OperatorAuthStreamInterceptorMiddleware(authenticator.Authenticate, service.ExecSpaceMethod))
+	}
 	server := grpc.NewServer(grpc.ChainUnaryInterceptor(
 		middleware.RecoveryInterceptorMiddleware(),
 		middleware.LogInterceptorMiddleware(),
-	))
-	pb.RegisterCloudIdeServiceServer(server, service.NewCloudSpaceService(client, manager))
+	), grpc.ChainStreamInterceptor(streamInterceptors...))
+	pb.RegisterCloudIdeServiceServer(server, service.NewCloudSpaceService(client, apiReader, manager, recorder, cfg, workspaceBackend, pool, puller, imageCatalog, queue, executor, estimator))
 
 	go func() {
 		err := server.Serve(listener)
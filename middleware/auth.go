@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// 调用方在metadata中携带操作人和令牌: x-cloud-ide-operator: <操作人>, authorization: Bearer <令牌>
+const (
+	OperatorMetadataKey      = "x-cloud-ide-operator"
+	AuthorizationMetadataKey = "authorization"
+)
+
+// Authenticator 校验操作人的令牌,失败时返回错误
+type Authenticator func(ctx context.Context, operator, token string) error
+
+type operatorKey struct{}
+
+// OperatorFromContext 返回认证通过的操作人,没有经过认证时返回空
+func OperatorFromContext(ctx context.Context) string {
+	operator, _ := ctx.Value(operatorKey{}).(string)
+	return operator
+}
+
+// OperatorAuthStreamInterceptorMiddleware 对methods中的流式调用认证操作人,认证通过后将操作人保存在上下文中
+func OperatorAuthStreamInterceptorMiddleware(authenticate Authenticator, methods ...string) grpc.StreamServerInterceptor {
+	protected := make(map[string]bool, len(methods))
+	for _, m := range methods {
+		protected[m] = true
+	}
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !protected[info.FullMethod] {
+			return handler(srv, ss)
+		}
+		md, _ := metadata.FromIncomingContext(ss.Context())
+		operator := strings.TrimSpace(first(md.Get(OperatorMetadataKey)))
+		token := strings.TrimSpace(strings.TrimPrefix(first(md.Get(AuthorizationMetadataKey)), "Bearer "))
+		if operator == "" || token == "" {
+			return status.Error(codes.Unauthenticated, "operator credentials are required")
+		}
+		if err := authenticate(ss.Context(), operator, token); err != nil {
+			klog.Warningf("authenticate operator %s for %s failed:%v", operator, info.FullMethod, err)
+			return status.Error(codes.PermissionDenied, "operator is not allowed")
+		}
+
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: context.WithValue(ss.Context(), operatorKey{}, operator)})
+	}
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
@@ -0,0 +1,71 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/middleware"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const execMethod = "/pb.CloudIdeService/execSpace"
+
+// fakeStream 只实现拦截器用到的Context
+type fakeStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeStream) Context() context.Context {
+	return s.ctx
+}
+
+// authenticate 只有alice使用令牌s3cr3t可以通过
+func authenticate(ctx context.Context, operator, token string) error {
+	if operator != "alice" || token != "s3cr3t" {
+		return errors.New("invalid token")
+	}
+	return nil
+}
+
+var _ = Describe("OperatorAuthStreamInterceptorMiddleware", func() {
+	DescribeTable("authenticates protected methods",
+		func(method string, md metadata.MD, code codes.Code, operator string) {
+			interceptor := middleware.OperatorAuthStreamInterceptorMiddleware(authenticate, execMethod)
+			called := false
+			handler := func(srv interface{}, ss grpc.ServerStream) error {
+				called = true
+				Expect(middleware.OperatorFromContext(ss.Context())).To(Equal(operator))
+				return nil
+			}
+			stream := &fakeStream{ctx: metadata.NewIncomingContext(context.Background(), md)}
+
+			err := interceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: method}, handler)
+			Expect(status.Code(err)).To(Equal(code))
+			Expect(called).To(Equal(code == codes.OK))
+		},
+		Entry("valid bearer token", execMethod,
+			metadata.Pairs(middleware.OperatorMetadataKey, "alice", middleware.AuthorizationMetadataKey, "Bearer s3cr3t"), codes.OK, "alice"),
+		Entry("token without the bearer prefix", execMethod,
+			metadata.Pairs(middleware.OperatorMetadataKey, "alice", middleware.AuthorizationMetadataKey, "s3cr3t"), codes.OK, "alice"),
+		Entry("wrong token", execMethod,
+			metadata.Pairs(middleware.OperatorMetadataKey, "alice", middleware.AuthorizationMetadataKey, "Bearer other"), codes.PermissionDenied, ""),
+		Entry("operator not allowed", execMethod,
+			metadata.Pairs(middleware.OperatorMetadataKey, "bob", middleware.AuthorizationMetadataKey, "Bearer s3cr3t"), codes.PermissionDenied, ""),
+		Entry("missing operator", execMethod,
+			metadata.Pairs(middleware.AuthorizationMetadataKey, "Bearer s3cr3t"), codes.Unauthenticated, ""),
+		Entry("missing token", execMethod,
+			metadata.Pairs(middleware.OperatorMetadataKey, "alice"), codes.Unauthenticated, ""),
+		Entry("blank token", execMethod,
+			metadata.Pairs(middleware.OperatorMetadataKey, "alice", middleware.AuthorizationMetadataKey, "Bearer  "), codes.Unauthenticated, ""),
+		Entry("blank operator", execMethod,
+			metadata.Pairs(middleware.OperatorMetadataKey, " ", middleware.AuthorizationMetadataKey, "Bearer s3cr3t"), codes.Unauthenticated, ""),
+		Entry("no metadata", execMethod, nil, codes.Unauthenticated, ""),
+		Entry("unprotected method", "/pb.CloudIdeService/createSpace", nil, codes.OK, ""),
+	)
+})
@@ -20,6 +20,20 @@ func LogInterceptorMiddleware() grpc.UnaryServerInterceptor {
 	}
 }
 
+// LogStreamInterceptorMiddleware 记录流式调用的日志
+func LogStreamInterceptorMiddleware() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, ss)
+		if err != nil {
+			klog.Errorf("stream %s closed with error:%v", info.FullMethod, err)
+		} else {
+			klog.Infof("stream %s closed", info.FullMethod)
+		}
+
+		return err
+	}
+}
+
 // RecoveryInterceptorMiddleware 防止panic导致整个服务崩溃
 func RecoveryInterceptorMiddleware() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
@@ -32,3 +46,16 @@ func RecoveryInterceptorMiddleware() grpc.UnaryServerInterceptor {
 		return handler(ctx, req)
 	}
 }
+
+// RecoveryStreamInterceptorMiddleware 防止流式调用中的panic导致整个服务崩溃
+func RecoveryStreamInterceptorMiddleware() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		defer func() {
+			if err := recover(); err != nil {
+				klog.Errorf("recovered from %v", err)
+			}
+		}()
+
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/pb"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/admission"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// tierDefault 没有调度配置时使用的层级
+const tierDefault = "default"
+
+// 没有排队时返回的状态
+const queueStateNotQueued = "NotQueued"
+
+// tier 工作空间所在的排队层级,与调度配置相同
+func (s *CloudSpaceService) tier(info *pb.WorkspaceInfo) string {
+	if info.SchedulingProfile != "" {
+		return info.SchedulingProfile
+	}
+	if s.cfg.Scheduling.DefaultProfile != "" {
+		return s.cfg.Scheduling.DefaultProfile
+	}
+
+	return tierDefault
+}
+
+// queueError 将排队的结果转换为返回给调用方的错误,其它错误返回nil,按创建Pod失败处理
+func (s *CloudSpaceService) queueError(pod *v1.Pod, err error) error {
+	switch err {
+	case context.DeadlineExceeded, context.Canceled:
+		// 调用方超时,请求仍然在队列中,资源释放后自动启动
+		pos, _ := s.admission.Position(client.ObjectKeyFromObject(pod))
+		klog.Infof("workspace is still queued, pod:%s, tier:%s, position:%d", pod.Name, pos.Tier, pos.Position)
+		return status.Error(codes.ResourceExhausted,
+			fmt.Sprintf("workspace is queued at position %d of %d, waiting for resources", pos.Position, pos.Depth))
+	case admission.ErrQueueTimeout:
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case admission.ErrQueueCanceled:
+		return status.Error(codes.Canceled, err.Error())
+	}
+
+	return nil
+}
+
+// WatchQueuePosition 观察工作空间在准入队列中的位置,位置变化时发送,出队或没有排队时结束
+func (s *CloudSpaceService) WatchQueuePosition(option *pb.QueryOption, stream pb.CloudIdeService_WatchQueuePositionServer) error {
+	key := client.ObjectKey{Name: option.Name, Namespace: option.Namespace}
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	var last *pb.QueuePosition
+	for {
+		pos, ok := s.admission.Position(key)
+		cur := &pb.QueuePosition{State: queueStateNotQueued}
+		if ok {
+			cur = &pb.QueuePosition{
+				Tier:     pos.Tier,
+				Position: int32(pos.Position),
+				Depth:    int32(pos.Depth),
+				QueuedAt: pos.QueuedAt.Unix(),
+				State:    pos.State,
+			}
+		}
+		if last == nil || last.Position != cur.Position || last.Depth != cur.Depth || last.State != cur.State {
+			if err := stream.Send(cur); err != nil {
+				return err
+			}
+			last = cur
+		}
+		if cur.State != admission.StateQueued {
+			return nil
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// CancelQueuedSpace 取消排队中的启动请求
+func (s *CloudSpaceService) CancelQueuedSpace(ctx context.Context, option *pb.QueryOption) (*pb.Response, error) {
+	if !s.admission.Cancel(ctx, client.ObjectKey{Name: option.Name, Namespace: option.Namespace}) {
+		return ResponseFailed, status.Error(codes.NotFound, ErrNotQueued.Error())
+	}
+	klog.Infof("[CancelQueuedSpace] queued request canceled, workspace:%s", option.Name)
+
+	return ResponseSuccess, nil
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+)
+
+// CheckCapacity 估算工作空间当前是否可以启动,使用与启动时相同的镜像、资源和调度配置构造Pod,
+// 根据节点剩余资源和ResourceQuota判断,结果只是估算
+func (s *CloudSpaceService) CheckCapacity(ctx context.Context, info *pb.WorkspaceInfo) (*pb.CapacityReport, error) {
+	if err := s.resolveImage(ctx, info); err != nil {
+		return &pb.CapacityReport{}, err
+	}
+	profile, err := s.schedulingProfile(info)
+	if err != nil {
+		return &pb.CapacityReport{}, err
+	}
+	if info.ResourceLimit == nil {
+		return &pb.CapacityReport{}, status.Error(codes.InvalidArgument, ErrInvalidResource.Error())
+	}
+	for _, q := range []string{info.ResourceLimit.Cpu, info.ResourceLimit.Memory} {
+		if _, err := resource.ParseQuantity(q); err != nil {
+			return &pb.CapacityReport{}, status.Error(codes.InvalidArgument, ErrInvalidResource.Error())
+		}
+	}
+
+	sidecars, err := s.sidecarContainers(info)
+	if err != nil {
+		return &pb.CapacityReport{}, err
+	}
+
+	pod := podTpl.DeepCopy()
+	s.fillPod(info, pod, info.Name)
+	pod.Spec.Containers = append(pod.Spec.Containers, sidecars...)
+	applySchedulingProfile(pod, profile)
+
+	report, err := s.estimator.Estimate(ctx, pod)
+	if err != nil {
+		klog.Errorf("estimate capacity error:%v", err)
+		return &pb.CapacityReport{}, status.Error(codes.Unknown, ErrCheckCapacity.Error())
+	}
+
+	return &pb.CapacityReport{
+		Fits:          report.Fits(),
+		EligibleNodes: int32(report.EligibleNodes),
+		FittingNodes:  int32(report.FittingNodes),
+		QuotaAllowed:  report.QuotaAllowed,
+		QueueDepth:    int32(s.admission.Depth(s.tier(info))),
+		Reasons:       report.Reasons,
+	}, nil
+}
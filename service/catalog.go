@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/pb"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/catalog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// ListImages 列出镜像目录中的开发环境,未启用镜像目录时返回空列表
+func (s *CloudSpaceService) ListImages(ctx context.Context, option *pb.ImageListOption) (*pb.CatalogImageList, error) {
+	images, err := s.catalog.List(ctx)
+	if err != nil {
+		klog.Errorf("list catalog images error:%v", err)
+		return &pb.CatalogImageList{}, status.Error(codes.Unknown, ErrListImages.Error())
+	}
+
+	list := &pb.CatalogImageList{}
+	for _, img := range images {
+		list.Images = append(list.Images, catalogImage(img))
+	}
+
+	return list, nil
+}
+
+// GetImage 获取镜像目录中的一个开发环境
+func (s *CloudSpaceService) GetImage(ctx context.Context, query *pb.ImageQuery) (*pb.CatalogImage, error) {
+	img, ok, err := s.catalog.Get(ctx, query.Id)
+	if err != nil {
+		klog.Errorf("get catalog image error:%v", err)
+		return &pb.CatalogImage{}, status.Error(codes.Unknown, ErrListImages.Error())
+	}
+	if !ok {
+		return &pb.CatalogImage{}, status.Error(codes.NotFound, ErrImageNotFound.Error())
+	}
+
+	return catalogImage(img), nil
+}
+
+// resolveImage 启用镜像目录时检查并补全工作空间的镜像:
+// 指定了目录ID或镜像在目录中时使用目录中固定digest的镜像,并为未指定的端口和资源填充默认值;
+// 调用方指定的digest必须与目录中固定的digest相同;不在目录中的镜像必须在白名单中
+func (s *CloudSpaceService) resolveImage(ctx context.Context, info *pb.WorkspaceInfo) error {
+	if s.catalog == nil {
+		return nil
+	}
+
+	var (
+		img catalog.Image
+		ok  bool
+		err error
+	)
+	if info.CatalogId != "" {
+		img, ok, err = s.catalog.Get(ctx, info.CatalogId)
+	} else {
+		img, ok, err = s.catalog.Find(ctx, info.Image)
+	}
+	if err != nil {
+		klog.Errorf("get catalog image error:%v", err)
+		return status.Error(codes.Unknown, ErrListImages.Error())
+	}
+	if !ok {
+		if info.CatalogId != "" {
+			return status.Error(codes.InvalidArgument, ErrImageNotFound.Error())
+		}
+		if !s.catalog.Allowed(info.Image) {
+			klog.Warningf("image is not allowed, image:%s", info.Image)
+			return status.Error(codes.PermissionDenied, ErrImageNotAllowed.Error())
+		}
+		info.Image = s.catalog.Resolve(ctx, info.Image)
+		return nil
+	}
+
+	pinned := s.catalog.Pin(ctx, img)
+	if info.CatalogId == "" && strings.Contains(info.Image, "@") && info.Image != pinned {
+		klog.Warningf("image digest does not match the catalog, image:%s, pinned:%s", info.Image, pinned)
+		return status.Errorf(codes.InvalidArgument, "%s: %s", ErrImageDigestMismatch.Error(), pinned)
+	}
+	info.Image = pinned
+	if info.Port == 0 {
+		info.Port = img.Port
+	}
+	if info.ResourceLimit == nil {
+		info.ResourceLimit = &pb.ResourceLimit{}
+	}
+	if info.ResourceLimit.Cpu == "" {
+		info.ResourceLimit.Cpu = img.CPU
+	}
+	if info.ResourceLimit.Memory == "" {
+		info.ResourceLimit.Memory = img.Memory
+	}
+	if info.ResourceLimit.Storage == "" {
+		info.ResourceLimit.Storage = img.Storage
+	}
+
+	return nil
+}
+
+func catalogImage(img catalog.Image) *pb.CatalogImage {
+	return &pb.CatalogImage{
+		Id:          img.ID,
+		DisplayName: img.DisplayName,
+		Description: img.Description,
+		Image:       img.Image,
+		Digest:      img.Digest,
+		Port:        img.Port,
+		ResourceLimit: &pb.ResourceLimit{
+			Cpu:     img.CPU,
+			Memory:  img.Memory,
+			Storage: img.Storage,
+		},
+	}
+}
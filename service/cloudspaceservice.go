@@ -2,14 +2,27 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"github.com/mangohow/cloud-ide-k8s-controller/conf"
 	"github.com/mangohow/cloud-ide-k8s-controller/pb"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/admission"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/backend"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/capacity"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/catalog"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/events"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/podexec"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/prepull"
 	"github.com/mangohow/cloud-ide-k8s-controller/tools/statussync"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/warmpool"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/workspace"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"time"
@@ -24,6 +37,7 @@ import (
 	在创建工作空间后第一次启动,需要将Code-Server的插件以及配置数据复制到存储卷中,
 	并且修改Code-Server的插件保存位置(默认为/root/.local中)
 	在后续的启动中就无需再次复制了(可以解决用户数据和Code-Server插件的保存,用户安装的程序在工作空间重新启动后就会消失)
+	复制由seed init容器完成,存储卷中的标记文件表示已经复制过,见seed.go
 */
 
 var Mode string
@@ -35,6 +49,9 @@ var (
 	ResponseFailed  = &pb.Response{Status: 400, Message: "failed"}
 )
 
+// StatusDeleting 工作空间正在删除,Message为当前进度
+const StatusDeleting int32 = 202
+
 var (
 	EmptyWorkspaceRunningInfo = &pb.WorkspaceRunningInfo{}
 	EmptyResponse             = &pb.Response{}
@@ -50,26 +67,100 @@ const (
 var _ pb.CloudIdeServiceServer = &CloudSpaceService{}
 
 type CloudSpaceService struct {
-	client         client.Client
+	client client.Client
+	// 直接读取API Server,用于读取缓存之外的资源
+	apiReader      client.Reader
 	statusInformer *statussync.StatusInformer
+	recorder       record.EventRecorder
+	cfg            *conf.Config
+	// 工作空间后端,创建和停止工作空间的Pod
+	backend backend.WorkspaceBackend
+	// 预热池,未启用时为nil
+	warmPool *warmpool.Pool
+	puller   *prepull.Puller
+	// 镜像目录,未启用时为nil
+	catalog *catalog.Catalog
+	// 准入队列,未启用时为nil
+	admission *admission.Queue
+	// 在工作空间容器中执行命令,未启用时为nil
+	executor *podexec.Executor
+	// 容量估算,与准入队列共用
+	estimator *capacity.Estimator
 }
 
-func NewCloudSpaceService(client client.Client, manager *statussync.StatusInformer) *CloudSpaceService {
+func NewCloudSpaceService(client client.Client, apiReader client.Reader, manager *statussync.StatusInformer, recorder record.EventRecorder,
+	cfg *conf.Config, backend backend.WorkspaceBackend, warmPool *warmpool.Pool, puller *prepull.Puller, catalog *catalog.Catalog,
+	admission *admission.Queue, executor *podexec.Executor, estimator *capacity.Estimator) *CloudSpaceService {
 	return &CloudSpaceService{
 		client:         client,
+		apiReader:      apiReader,
 		statusInformer: manager,
+		recorder:       recorder,
+		cfg:            cfg,
+		backend:        backend,
+		warmPool:       warmPool,
+		puller:         puller,
+		catalog:        catalog,
+		admission:      admission,
+		executor:       executor,
+		estimator:      estimator,
 	}
 }
 
 // CreateSpace 创建云IDE空间并等待Pod状态变为Running,第一次创建,需要挂载存储卷
 func (s *CloudSpaceService) CreateSpace(ctx context.Context, info *pb.WorkspaceInfo) (*pb.WorkspaceRunningInfo, error) {
+	// devcontainer.json中的镜像同样需要解析
+	annotations, err := devcontainerAnnotations(info)
+	if err != nil {
+		return EmptyWorkspaceRunningInfo, err
+	}
+	if err := s.resolveImage(ctx, info); err != nil {
+		return EmptyWorkspaceRunningInfo, err
+	}
+	// 在创建PVC之前检查调度配置和附加服务
+	if _, err := s.schedulingProfile(info); err != nil {
+		return EmptyWorkspaceRunningInfo, err
+	}
+	if err := validatePorts(info); err != nil {
+		return EmptyWorkspaceRunningInfo, err
+	}
+	if _, err := s.sidecarContainers(info); err != nil {
+		return EmptyWorkspaceRunningInfo, err
+	}
+	if info.Owner != "" {
+		if err := validateOwner(info.Owner); err != nil {
+			return EmptyWorkspaceRunningInfo, err
+		}
+	}
+	git, err := s.gitSource(ctx, info)
+	if err != nil {
+		return EmptyWorkspaceRunningInfo, err
+	}
 	// 1. 创建pvc,pvc的name和pod相同
 	pvcName := info.Name
 	pvc, err := s.constructPVC(pvcName, info.Namespace, info.ResourceLimit.Storage)
 	if err != nil {
 		klog.Errorf("construct pvc error:%v, info:%v", err, info)
+		// PVC还没有创建,事件关联到将要创建的PVC名称上
+		ref := &v1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: pvcName, Namespace: info.Namespace}}
+		s.recorder.Eventf(ref, v1.EventTypeWarning, events.ReasonConstructPVCFailed, "construct pvc failed: %v", err)
 		return EmptyWorkspaceRunningInfo, status.Error(codes.Unknown, ErrConstructPVC.Error())
 	}
+	// Git源保存在PVC上,第一次启动时克隆
+	if git != nil {
+		data, err := json.Marshal(git)
+		if err != nil {
+			return EmptyWorkspaceRunningInfo, status.Error(codes.Unknown, err.Error())
+		}
+		annotations[workspace.AnnotationGitSource] = string(data)
+	}
+	if len(annotations) > 0 {
+		pvc.Annotations = annotations
+	}
+	// 启动时同步该用户的配置
+	if info.Owner != "" {
+		pvc.Labels[workspace.LabelOwner] = info.Owner
+	}
 	klog.Infof("[CreateSpace] 1.construct pvc")
 	deadline, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
@@ -78,10 +169,18 @@ func (s *CloudSpaceService) CreateSpace(ctx context.Context, info *pb.WorkspaceI
 		// 如果PVC已经存在
 		if errors.IsAlreadyExists(err) {
 			klog.Infof("create pvc while pvc is already exist, pvc:%s", pvcName)
+			// 同名的工作空间在回收站中,不能创建
+			exist := &v1.PersistentVolumeClaim{}
+			if err := s.apiReader.Get(deadline, client.ObjectKeyFromObject(pvc), exist); err == nil && exist.Labels[workspace.LabelState] == workspace.StateTrashed {
+				return EmptyWorkspaceRunningInfo, status.Error(codes.AlreadyExists, ErrNameTrashed.Error())
+			}
 		} else {
 			klog.Errorf("create pvc error:%v", err)
+			s.recorder.Eventf(pvc, v1.EventTypeWarning, events.ReasonCreatePVCFailed, "create pvc failed: %v", err)
 			return EmptyWorkspaceRunningInfo, status.Error(codes.Unknown, ErrConstructPVC.Error())
 		}
+	} else {
+		s.recorder.Eventf(pvc, v1.EventTypeNormal, events.ReasonPVCCreated, "created pvc %s, storage %s", pvcName, info.ResourceLimit.Storage)
 	}
 	klog.Info("[CreateSpace] 2.create pvc success")
 
@@ -90,56 +189,155 @@ func (s *CloudSpaceService) CreateSpace(ctx context.Context, info *pb.WorkspaceI
 }
 
 func (s *CloudSpaceService) createPod(c context.Context, info *pb.WorkspaceInfo) (*pb.WorkspaceRunningInfo, error) {
+	// Pod以PVC为owner,工作空间删除时随PVC一起回收
+	pvc := &v1.PersistentVolumeClaim{}
+	err := s.client.Get(c, client.ObjectKey{Name: info.Name, Namespace: info.Namespace}, pvc)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return EmptyWorkspaceRunningInfo, status.Error(codes.NotFound, ErrWorkspaceNotFound.Error())
+		}
+		klog.Errorf("get pvc error:%v", err)
+		return EmptyWorkspaceRunningInfo, status.Error(codes.Unknown, ErrCreatePod.Error())
+	}
+	if pvc.DeletionTimestamp != nil {
+		return EmptyWorkspaceRunningInfo, status.Error(codes.FailedPrecondition, ErrWorkspaceDeleting.Error())
+	}
+	if pvc.Labels[workspace.LabelState] == workspace.StateTrashed {
+		return EmptyWorkspaceRunningInfo, status.Error(codes.FailedPrecondition, ErrWorkspaceTrashed.Error())
+	}
+
+	// 管理员升级过镜像的工作空间使用升级后的镜像
+	if image := pvc.Annotations[workspace.AnnotationImage]; image != "" {
+		info.Image = image
+	}
+
+	profile, err := s.schedulingProfile(info)
+	if err != nil {
+		return EmptyWorkspaceRunningInfo, err
+	}
+	if err := validatePorts(info); err != nil {
+		return EmptyWorkspaceRunningInfo, err
+	}
+	if err := s.unexposeWorkspacePort(c, pvc, info.Port); err != nil {
+		klog.Errorf("unexpose workspace port error:%v", err)
+		return EmptyWorkspaceRunningInfo, status.Error(codes.Unknown, ErrCreatePod.Error())
+	}
+	sidecars, err := s.sidecarContainers(info)
+	if err != nil {
+		return EmptyWorkspaceRunningInfo, err
+	}
+
 	pod := podTpl.DeepCopy()
 	s.fillPod(info, pod, info.Name)
+	// 附加服务与工作空间共享网络,Pod在所有容器都就绪后才会就绪
+	pod.Spec.Containers = append(pod.Spec.Containers, sidecars...)
+	warnings := s.applyDevcontainer(info, pod, pvc)
+	s.applySeed(c, pod, info.Image)
+	s.applyGitClone(c, pod, pvc)
+	s.applyProfile(c, pod, pvc)
+	s.applySSH(c, pod, pvc)
+	applySchedulingProfile(pod, profile)
+	workspace.SetOwner(pod, pvc)
+	// 启动截止时间保存在Pod的注解中,超时未就绪的Pod由PodReconciler删除,控制器重启后也能继续处理
+	deadline := time.Now().Add(s.cfg.StartTimeout.Duration)
+	if d, ok := c.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	workspace.SetStarting(pod, deadline)
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancel()
-	err := s.client.Create(ctx, pod)
+	if s.cfg.Drain.PDB {
+		// 节点排空时驱逐被阻止,由控制器通知用户后迁移工作空间,创建失败不影响启动
+		if err := workspace.EnsurePDB(ctx, s.client, pvc); err != nil {
+			klog.Errorf("create pdb error:%v, workspace:%s", err, info.Name)
+		}
+	}
+	s.claimWarmNode(ctx, info, pod)
+	if s.admission != nil {
+		// 资源足够时直接启动,不足时按层级排队,Pod调度成功后再等待就绪
+		err = s.admission.Wait(c, s.tier(info), pod)
+		if err != nil && !errors.IsAlreadyExists(err) {
+			if qerr := s.queueError(pod, err); qerr != nil {
+				return EmptyWorkspaceRunningInfo, qerr
+			}
+		}
+	} else {
+		err = s.backend.Start(ctx, pod)
+	}
 	if err != nil {
 		// 如果该Pod已经存在
 		if errors.IsAlreadyExists(err) {
 			klog.Infof("create pod while pod is already exist, pod:%s", info.Name)
-			// 判断Pod是否处于running状态
-			existPod := v1.Pod{}
-			err = s.client.Get(context.Background(), client.ObjectKeyFromObject(pod), &existPod)
+			// 判断Pod是否已经就绪
+			existPod, err := s.backend.Get(context.Background(), info.Namespace, info.Name)
 			if err != nil {
 				return EmptyWorkspaceRunningInfo, status.Error(codes.Unknown, ErrCreatePod.Error())
 			}
-			if existPod.Status.Phase == v1.PodRunning {
-				return &pb.WorkspaceRunningInfo{
-					NodeName: existPod.Spec.NodeName,
-					Ip:       existPod.Status.PodIP,
-					Port:     existPod.Spec.Containers[0].Ports[0].ContainerPort,
-				}, nil
+			if workspace.IsPodReady(existPod) {
+				return runningInfo(existPod), nil
+			} else if d, ok := workspace.StartDeadline(existPod); ok && time.Now().Before(d) && existPod.DeletionTimestamp == nil {
+				// Pod仍在启动中(例如上一次调用者已经超时或控制器重启过),继续等待
+				klog.Infof("pod is still starting, wait for it, pod:%s", info.Name)
+				return s.waitForReady(c, existPod)
 			} else {
-				s.deletePod(&existPod)
+				s.deletePod(existPod, workspace.StopReasonNotRunning)
 				return EmptyWorkspaceRunningInfo, status.Error(codes.Unknown, ErrCreatePod.Error())
 			}
 
 		} else {
 			klog.Errorf("create pod err:%v", err)
+			s.recorder.Eventf(pod, v1.EventTypeWarning, events.ReasonCreatePodFailed, "create pod failed: %v", err)
 			return EmptyWorkspaceRunningInfo, status.Error(codes.Unknown, ErrCreatePod.Error())
 		}
 	}
 
 	klog.Info("[createPod] create pod success")
+	s.recorder.Eventf(pod, v1.EventTypeNormal, events.ReasonPodCreated, "created pod with image %s", info.Image)
+
+	res, err := s.waitForReady(c, pod)
+	if err == nil {
+		res.Warnings = warnings
+	}
+
+	return res, err
+}
+
+// claimWarmNode 占用一个预热Pod,工作空间优先调度到它所在的节点,该节点已经拉取了镜像。
+// 只在会创建新Pod时占用,Pod已经存在或正在排队时启动会返回AlreadyExists,占用的预热Pod会被白白删除
+func (s *CloudSpaceService) claimWarmNode(ctx context.Context, info *pb.WorkspaceInfo, pod *v1.Pod) {
+	if s.warmPool == nil {
+		return
+	}
+	if _, err := s.backend.Get(ctx, info.Namespace, info.Name); !errors.IsNotFound(err) {
+		return
+	}
+	if _, ok := s.admission.Position(client.ObjectKeyFromObject(pod)); ok {
+		return
+	}
+	if node, ok := s.warmPool.Claim(ctx, catalog.Unpinned(info.Image)); ok {
+		warmpool.PreferNode(pod, node)
+	}
+}
+
+// waitForReady 等待Pod就绪,调用方超时后直接返回,超时的Pod由PodReconciler在截止时间后删除
+func (s *CloudSpaceService) waitForReady(c context.Context, pod *v1.Pod) (*pb.WorkspaceRunningInfo, error) {
 	// 向informer中添加chan，当Pod准备就绪时就会收到通知
-	ch := s.statusInformer.Add(pod.Name)
+	// 以工作空间名称通知,StatefulSet创建的Pod名称与工作空间不同
+	name := workspace.Name(pod)
+	ch := s.statusInformer.Add(name)
 	// 从informer中删除
-	defer s.statusInformer.Delete(pod.Name)
+	defer s.statusInformer.Delete(name)
 
 	select {
-	// 等待pod状态处于Running
+	// 等待pod就绪(code-server通过就绪探针)
 	case <-ch:
-		// Pod已经处于running状态
-		return s.GetPodSpaceInfo(context.Background(), &pb.QueryOption{Name: info.Name, Namespace: info.Namespace})
+		// Pod已经就绪
+		return s.GetPodSpaceInfo(context.Background(), &pb.QueryOption{Name: name, Namespace: pod.Namespace})
 	case <-c.Done():
-		// 超时,Pod启动失败,可能是由于资源不足,将Pod删除
-		klog.Error("pod start failed, maybe resources is not enough")
-		s.deletePod(pod)
+		// 超时,Pod启动失败,可能是由于资源不足
+		klog.Errorf("pod start failed, maybe resources is not enough, pod:%s", pod.Name)
 		return EmptyWorkspaceRunningInfo, status.Error(codes.Unknown, ErrCreatePod.Error())
 	}
-
 }
 
 /*
@@ -171,6 +369,12 @@ func (s *CloudSpaceService) constructPVC(name, namespace, storage string) (*v1.P
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
 			Namespace: namespace,
+			Labels: map[string]string{
+				workspace.LabelKind:      workspace.KindCloudIde,
+				workspace.LabelWorkspace: name,
+			},
+			// 删除时由PVCReconciler先清理Pod和附属资源
+			Finalizers: []string{workspace.FinalizerCleanup},
 		},
 		Spec: v1.PersistentVolumeClaimSpec{
 			AccessModes: []v1.PersistentVolumeAccessMode{v1.ReadWriteMany},
@@ -206,10 +410,17 @@ spec:
       readOnly: false
 */
 
+// 工作空间存储卷在Pod中的名称和挂载路径
+const (
+	workspaceVolume    = "volume-user-workspace"
+	workspaceMountPath = "/user_data/"
+)
+
 func (s *CloudSpaceService) fillPod(info *pb.WorkspaceInfo, pod *v1.Pod, pvc string) {
-	volumeName := "volume-user-workspace"
+	volumeName := workspaceVolume
 	pod.Name = info.Name
 	pod.Namespace = info.Namespace
+	pod.Labels[workspace.LabelWorkspace] = info.Name
 	// 配置持久化存储
 	pod.Spec.Volumes = []v1.Volume{
 		v1.Volume{
@@ -227,17 +438,15 @@ func (s *CloudSpaceService) fillPod(info *pb.WorkspaceInfo, pod *v1.Pod, pvc str
 			Name:            info.Name,
 			Image:           info.Image,
 			ImagePullPolicy: v1.PullIfNotPresent,
-			Ports: []v1.ContainerPort{
-				{
-					ContainerPort: int32(info.Port),
-				},
-			},
+			Ports:           containerPorts(info),
+			// Pod处于Running时code-server可能还没有开始监听,通过就绪探针判断是否可以访问
+			ReadinessProbe: s.readinessProbe(info),
 			// 容器挂载存储卷
 			VolumeMounts: []v1.VolumeMount{
 				v1.VolumeMount{
 					Name:      volumeName,
 					ReadOnly:  false,
-					MountPath: "/user_data/",
+					MountPath: workspaceMountPath,
 				},
 			},
 		},
@@ -256,45 +465,138 @@ func (s *CloudSpaceService) fillPod(info *pb.WorkspaceInfo, pod *v1.Pod, pvc str
 			},
 		}
 	}
+	workspace.SetGracefulStop(pod, s.cfg.Drain.GracePeriod.Duration, s.cfg.Drain.PreStop)
+}
+
+// containerPorts 工作空间的端口在第一个,之后是应用使用的具名端口
+func containerPorts(info *pb.WorkspaceInfo) []v1.ContainerPort {
+	ports := []v1.ContainerPort{{ContainerPort: info.Port}}
+	for _, p := range info.Ports {
+		ports = append(ports, v1.ContainerPort{Name: p.Name, ContainerPort: p.Port})
+	}
+
+	return ports
+}
+
+func (s *CloudSpaceService) readinessProbe(info *pb.WorkspaceInfo) *v1.Probe {
+	cfg := s.cfg.Readiness
+	port := cfg.Port
+	if port == 0 {
+		port = info.Port
+	}
 
+	return &v1.Probe{
+		ProbeHandler: v1.ProbeHandler{
+			HTTPGet: &v1.HTTPGetAction{
+				Path: cfg.Path,
+				Port: intstr.FromInt(int(port)),
+			},
+		},
+		InitialDelaySeconds: cfg.InitialDelaySeconds,
+		PeriodSeconds:       cfg.PeriodSeconds,
+		FailureThreshold:    cfg.FailureThreshold,
+	}
 }
 
 // StartSpace 启动(创建)云IDE空间,非第一次创建,无需挂载存储卷,使用之前的存储卷
 func (s *CloudSpaceService) StartSpace(ctx context.Context, info *pb.WorkspaceInfo) (*pb.WorkspaceRunningInfo, error) {
+	// 使用创建时或从仓库中读取的devcontainer.json中的镜像,PVC不存在时由createPod返回错误
+	pvc := &v1.PersistentVolumeClaim{}
+	if err := s.client.Get(ctx, client.ObjectKey{Name: info.Name, Namespace: info.Namespace}, pvc); err == nil {
+		if spec, _ := storedDevcontainer(pvc); spec != nil {
+			useDevcontainerImage(info, spec)
+		}
+	}
+	if err := s.resolveImage(ctx, info); err != nil {
+		return EmptyWorkspaceRunningInfo, err
+	}
+
 	return s.createPod(ctx, info)
 }
 
-// DeleteSpace 删除云IDE空间, 只需要删除存储卷
+// DeleteSpace 删除云IDE空间,回收站保留期大于0时停止Pod并移入回收站,
+// 否则依次删除Pod、附属资源(Service、Secret、Ingress)和存储卷,
+// 在等待时间内没有删除完成时返回当前进度(Status为202),可以重复调用直到返回成功
 func (s *CloudSpaceService) DeleteSpace(ctx context.Context, option *pb.QueryOption) (*pb.Response, error) {
-	// 删除pvc
-	pvc := &v1.PersistentVolumeClaim{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      option.Name,
-			Namespace: option.Namespace,
-		},
-	}
+	pvc := &v1.PersistentVolumeClaim{}
 	c, cancelFunc := context.WithTimeout(context.Background(), time.Second*30)
 	defer cancelFunc()
-	err := s.client.Delete(c, pvc)
+	err := s.client.Get(c, client.ObjectKey{Name: option.Name, Namespace: option.Namespace}, pvc)
 	if err != nil {
 		// 如果是PVC不存在引起的错误就认为是成功了,因为就是要删除PVC
 		if errors.IsNotFound(err) {
 			klog.Infof("pvc not found,err:%v", err)
 			return ResponseSuccess, nil
 		}
-		klog.Errorf("delete pvc error:%v", err)
+		klog.Errorf("get pvc error:%v", err)
 		return ResponseFailed, status.Error(codes.Unknown, ErrDeletePVC.Error())
 	}
-	klog.Info("[DeleteSpace] delete pvc success")
 
-	return ResponseSuccess, nil
+	// 移入回收站,由TrashPurger在保留期过后彻底删除
+	if s.cfg.Trash.Retention.Duration > 0 && pvc.DeletionTimestamp == nil {
+		return s.trashSpace(c, pvc)
+	}
+
+	if pvc.DeletionTimestamp == nil {
+		err = workspace.DeletePVC(c, s.client, pvc)
+		if err != nil && !errors.IsNotFound(err) {
+			klog.Errorf("delete pvc error:%v", err)
+			s.recorder.Eventf(pvc, v1.EventTypeWarning, events.ReasonDeletePVCFailed, "delete pvc failed: %v", err)
+			return ResponseFailed, status.Error(codes.Unknown, ErrDeletePVC.Error())
+		}
+		klog.Info("[DeleteSpace] delete pvc requested")
+		s.recorder.Event(pvc, v1.EventTypeNormal, events.ReasonPVCDeleted, "workspace deletion requested")
+	}
+
+	// 等待删除完成,Pod的删除由PVCReconciler和这里共同推进,重复删除是安全的
+	stage, err := s.waitForDeleted(ctx, pvc)
+	if err != nil {
+		klog.Errorf("cleanup workspace error:%v", err)
+		return ResponseFailed, status.Error(codes.Unknown, ErrDeletePVC.Error())
+	}
+	if stage == workspace.StageDeleted {
+		klog.Info("[DeleteSpace] delete pvc success")
+		return ResponseSuccess, nil
+	}
+
+	return &pb.Response{Status: StatusDeleting, Message: stage}, nil
+}
+
+// waitForDeleted 推进工作空间的删除,直到PVC被删除或者超过等待时间,返回当前进度
+func (s *CloudSpaceService) waitForDeleted(ctx context.Context, pvc *v1.PersistentVolumeClaim) (string, error) {
+	timer := time.NewTimer(s.cfg.DeleteWaitTimeout.Duration)
+	defer timer.Stop()
+	ticker := time.NewTicker(time.Millisecond * 500)
+	defer ticker.Stop()
+	for {
+		c, cancel := context.WithTimeout(context.Background(), time.Second*10)
+		err := s.client.Get(c, client.ObjectKeyFromObject(pvc), &v1.PersistentVolumeClaim{})
+		if errors.IsNotFound(err) {
+			cancel()
+			return workspace.StageDeleted, nil
+		}
+		stage, err := workspace.Cleanup(c, s.client, pvc.Namespace, pvc.Name)
+		cancel()
+		if err != nil {
+			return "", err
+		}
+
+		select {
+		case <-ticker.C:
+		case <-timer.C:
+			return stage, nil
+		case <-ctx.Done():
+			return stage, nil
+		}
+	}
 }
 
-func (s *CloudSpaceService) deletePod(pod *v1.Pod) (*pb.Response, error) {
+// deletePod 删除Pod,reason为删除原因
+func (s *CloudSpaceService) deletePod(pod *v1.Pod, reason string) (*pb.Response, error) {
 	// k8s的默认最大宽限时间为30s,因此在这设置为32s
 	ctx, cancelFunc := context.WithTimeout(context.Background(), time.Second*32)
 	defer cancelFunc()
-	err := s.client.Delete(ctx, pod)
+	err := s.backend.Stop(ctx, pod.Namespace, workspace.Name(pod), reason)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			klog.Infof("delete pod while pod not exist, pod:%s", pod.Name)
@@ -302,9 +604,11 @@ func (s *CloudSpaceService) deletePod(pod *v1.Pod) (*pb.Response, error) {
 		}
 
 		klog.Errorf("delete pod error:%v", err)
+		s.recorder.Eventf(pod, v1.EventTypeWarning, events.ReasonDeletePodFailed, "delete pod failed: %v", err)
 		return ResponseFailed, status.Error(codes.Unknown, ErrDeletePod.Error())
 	}
 	klog.Info("[deletePod] delete pod success")
+	s.recorder.Event(pod, v1.EventTypeNormal, events.ReasonPodDeleted, "deleted pod")
 
 	return ResponseSuccess, nil
 }
@@ -317,14 +621,21 @@ func (s *CloudSpaceService) StopSpace(ctx context.Context, option *pb.QueryOptio
 			Namespace: option.Namespace,
 		},
 	}
+	// 先获取一次,使Event能够关联到该Pod(需要UID),获取失败不影响删除
+	if existPod, err := s.backend.Get(ctx, option.Namespace, option.Name); err == nil {
+		pod = existPod
+	}
+	// 排队中的请求直接取消
+	if s.admission.Cancel(ctx, client.ObjectKey{Name: option.Name, Namespace: option.Namespace}) {
+		return ResponseSuccess, nil
+	}
 
-	return s.deletePod(pod)
+	return s.deletePod(pod, workspace.StopReasonRequested)
 }
 
 // GetPodSpaceStatus 获取Pod运行状态
 func (s *CloudSpaceService) GetPodSpaceStatus(ctx context.Context, option *pb.QueryOption) (*pb.WorkspaceStatus, error) {
-	pod := v1.Pod{}
-	err := s.client.Get(ctx, client.ObjectKey{Name: option.Name, Namespace: option.Namespace}, &pod)
+	pod, err := s.backend.Get(ctx, option.Namespace, option.Name)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			return EmptyWorkspaceStatus, status.Error(codes.NotFound, "pod not found")
@@ -334,13 +645,27 @@ func (s *CloudSpaceService) GetPodSpaceStatus(ctx context.Context, option *pb.Qu
 		return &pb.WorkspaceStatus{Status: PodNotExist, Message: "NotExist"}, status.Error(codes.Unknown, err.Error())
 	}
 
-	return &pb.WorkspaceStatus{Status: PodExist, Message: string(pod.Status.Phase)}, nil
+	res := &pb.WorkspaceStatus{Status: PodExist, Message: string(pod.Status.Phase)}
+	// 期望状态和自动重启记录保存在PVC上
+	pvc := &v1.PersistentVolumeClaim{}
+	if err := s.client.Get(ctx, client.ObjectKey{Name: option.Name, Namespace: option.Namespace}, pvc); err == nil {
+		res.DesiredState = pvc.Annotations[workspace.AnnotationDesiredState]
+		for _, record := range workspace.RestartHistory(pvc) {
+			res.Restarts = append(res.Restarts, &pb.RestartRecord{
+				Time:     record.Time.Unix(),
+				Reason:   record.Reason,
+				NodeName: record.NodeName,
+			})
+		}
+		res.Conditions = gitConditions(pvc)
+	}
+
+	return res, nil
 }
 
 // GetPodSpaceInfo 获取云IDE空间Pod的信息
 func (s *CloudSpaceService) GetPodSpaceInfo(ctx context.Context, option *pb.QueryOption) (*pb.WorkspaceRunningInfo, error) {
-	pod := v1.Pod{}
-	err := s.client.Get(ctx, client.ObjectKey{Name: option.Name, Namespace: option.Namespace}, &pod)
+	pod, err := s.backend.Get(ctx, option.Namespace, option.Name)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			return EmptyWorkspaceRunningInfo, status.Error(codes.NotFound, "pod not found")
@@ -350,9 +675,46 @@ func (s *CloudSpaceService) GetPodSpaceInfo(ctx context.Context, option *pb.Quer
 		return EmptyWorkspaceRunningInfo, status.Error(codes.Unknown, err.Error())
 	}
 
-	return &pb.WorkspaceRunningInfo{NodeName: pod.Spec.NodeName,
-		Ip:   pod.Status.PodIP,
-		Port: pod.Spec.Containers[0].Ports[0].ContainerPort}, nil
+	res := runningInfo(pod)
+	res.ExposedPorts = s.exposedPorts(ctx, option.Namespace, option.Name)
+	res.SshCommand = s.sshCommand(ctx, option.Namespace, option.Name)
+
+	return res, nil
+}
+
+func runningInfo(pod *v1.Pod) *pb.WorkspaceRunningInfo {
+	info := &pb.WorkspaceRunningInfo{
+		NodeName: pod.Spec.NodeName,
+		Ip:       pod.Status.PodIP,
+		Port:     pod.Spec.Containers[0].Ports[0].ContainerPort,
+	}
+	container := workspace.Container(pod)
+	for _, p := range container.Ports {
+		info.Ports = append(info.Ports, &pb.NamedPort{Name: p.Name, Port: p.ContainerPort})
+	}
+	name := container.Name
+	for _, cs := range pod.Status.ContainerStatuses {
+		info.Containers = append(info.Containers, &pb.ContainerStatus{
+			Name:         cs.Name,
+			Ready:        cs.Ready,
+			RestartCount: cs.RestartCount,
+			State:        containerState(cs.State),
+			Sidecar:      cs.Name != name,
+		})
+	}
+
+	return info
+}
+
+func containerState(state v1.ContainerState) string {
+	switch {
+	case state.Running != nil:
+		return "Running"
+	case state.Terminated != nil:
+		return "Terminated"
+	default:
+		return "Waiting"
+	}
 }
 
 var podTpl = &v1.Pod{
@@ -362,7 +724,7 @@ var podTpl = &v1.Pod{
 	},
 	ObjectMeta: metav1.ObjectMeta{
 		Labels: map[string]string{
-			"kind": "cloud-ide",
+			workspace.LabelKind: workspace.KindCloudIde,
 		},
 	},
 }
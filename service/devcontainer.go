@@ -0,0 +1,85 @@
+package service
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/pb"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/devcontainer"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/workspace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
+)
+
+// devcontainerAnnotations 校验CreateSpace中的devcontainer配置,返回需要保存在PVC上的注解,
+// 同时指定内容和仓库路径时使用内容
+func devcontainerAnnotations(info *pb.WorkspaceInfo) (map[string]string, error) {
+	annotations := make(map[string]string)
+	if info.Devcontainer != "" {
+		data, err := devcontainer.Compact([]byte(info.Devcontainer))
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%s: %v", ErrInvalidDevcontainer.Error(), err)
+		}
+		spec, _, _ := devcontainer.Parse([]byte(data))
+		annotations[workspace.AnnotationDevcontainer] = data
+		useDevcontainerImage(info, spec)
+		return annotations, nil
+	}
+
+	if info.DevcontainerPath != "" {
+		if info.Git == nil || info.Git.Url == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "%s: devcontainerPath requires git", ErrInvalidDevcontainer.Error())
+		}
+		p := path.Clean(info.DevcontainerPath)
+		if path.IsAbs(p) || p == ".." || strings.HasPrefix(p, "../") {
+			return nil, status.Errorf(codes.InvalidArgument, "%s: invalid devcontainerPath", ErrInvalidDevcontainer.Error())
+		}
+		annotations[workspace.AnnotationDevcontainerPath] = p
+	}
+
+	return annotations, nil
+}
+
+// storedDevcontainer 解析PVC上保存的devcontainer.json,没有时返回nil
+func storedDevcontainer(pvc *v1.PersistentVolumeClaim) (*devcontainer.Spec, []string) {
+	data := pvc.Annotations[workspace.AnnotationDevcontainer]
+	if data == "" {
+		return nil, nil
+	}
+	spec, warnings, err := devcontainer.Parse([]byte(data))
+	if err != nil {
+		return nil, []string{fmt.Sprintf("devcontainer.json ignored: %v", err)}
+	}
+
+	return spec, warnings
+}
+
+// useDevcontainerImage 没有指定镜像目录中的环境时使用devcontainer.json中的镜像,镜像同样需要通过白名单检查
+func useDevcontainerImage(info *pb.WorkspaceInfo, spec *devcontainer.Spec) {
+	if spec.Image != "" && info.CatalogId == "" {
+		info.Image = spec.Image
+	}
+}
+
+// applyDevcontainer 将PVC上保存的devcontainer.json转换到工作空间Pod上,返回被忽略的配置
+func (s *CloudSpaceService) applyDevcontainer(info *pb.WorkspaceInfo, pod *v1.Pod, pvc *v1.PersistentVolumeClaim) []string {
+	spec, warnings := storedDevcontainer(pvc)
+	if spec == nil {
+		if p := pvc.Annotations[workspace.AnnotationDevcontainerPath]; p != "" && len(warnings) == 0 {
+			if workspace.GitCloned(pvc) {
+				warnings = append(warnings, fmt.Sprintf("%s not found in the cloned repository", p))
+			} else {
+				warnings = append(warnings, fmt.Sprintf("%s will be applied from the next start after the repository is cloned", p))
+			}
+		}
+		return warnings
+	}
+
+	if spec.Image != "" && info.CatalogId != "" {
+		warnings = append(warnings, "image ignored, catalogId takes precedence")
+	}
+
+	return append(warnings, devcontainer.Apply(pod, &pod.Spec.Containers[0], spec, workspaceVolume, workspaceMountPath, s.cfg.Devcontainer.AllowedUIDs)...)
+}
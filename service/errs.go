@@ -8,4 +8,54 @@ var (
 	ErrCreatePod    = errors.New("create pod failed")
 	ErrDeletePod    = errors.New("delete pod failed")
 	ErrDeletePVC    = errors.New("delete pvc failed")
+
+	ErrWorkspaceNotFound = errors.New("workspace not found")
+	ErrWorkspaceDeleting = errors.New("workspace is being deleted")
+	ErrWorkspaceTrashed  = errors.New("workspace is in trash, restore it first")
+	ErrNameTrashed       = errors.New("a workspace with the same name is in trash, restore it or wait until it is purged")
+	ErrRestoreSpace      = errors.New("restore workspace failed")
+	ErrListTrashedSpaces = errors.New("list trashed workspaces failed")
+
+	ErrGetImageCacheStatus = errors.New("get image cache status failed")
+	ErrListImages          = errors.New("list catalog images failed")
+	ErrImageNotFound       = errors.New("image not found in catalog")
+	ErrImageNotAllowed     = errors.New("image is not in catalog or allowlist")
+	ErrImageDigestMismatch = errors.New("image digest does not match the catalog")
+	ErrCatalogDisabled     = errors.New("image catalog is not enabled")
+	ErrUpgradeSpaces       = errors.New("upgrade workspaces failed")
+
+	ErrSchedulingProfile = errors.New("scheduling profile not found")
+
+	ErrNotQueued = errors.New("workspace is not queued")
+
+	ErrCheckCapacity   = errors.New("check capacity failed")
+	ErrInvalidResource = errors.New("invalid resource limit")
+
+	ErrInvalidGitSource    = errors.New("invalid git source")
+	ErrGitSecretNotFound   = errors.New("git credentials secret not found")
+	ErrGitSecretNotAllowed = errors.New("git credentials secret must be labelled with kind=cloud-ide-git-credentials and the workspace owner")
+
+	ErrInvalidDevcontainer = errors.New("invalid devcontainer")
+	ErrInvalidSidecar      = errors.New("invalid sidecar")
+
+	ErrInvalidOwner   = errors.New("invalid owner")
+	ErrInvalidProfile = errors.New("invalid user profile")
+	ErrGetProfile     = errors.New("get user profile failed")
+	ErrUpdateProfile  = errors.New("update user profile failed")
+
+	ErrInvalidPort     = errors.New("invalid port")
+	ErrPreviewDisabled = errors.New("port preview is not enabled")
+	ErrExposePort      = errors.New("expose port failed")
+	ErrUnexposePort    = errors.New("unexpose port failed")
+
+	ErrInvalidSSHKey = errors.New("invalid ssh public key")
+	ErrGetSSHKeys    = errors.New("get ssh keys failed")
+	ErrSetSSHKeys    = errors.New("set ssh keys failed")
+
+	ErrExecDisabled      = errors.New("exec is not enabled")
+	ErrInvalidExec       = errors.New("invalid exec request")
+	ErrExecUnauthorized  = errors.New("operator is not allowed to exec")
+	ErrWorkspaceNotReady = errors.New("workspace is not ready")
+	ErrExec              = errors.New("exec failed")
+	ErrExecTerminated    = errors.New("exec session terminated")
 )
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/middleware"
+	"github.com/mangohow/cloud-ide-k8s-controller/pb"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/events"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/podexec"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/workspace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/klog/v2"
+)
+
+// ExecSpaceMethod execSpace的gRPC方法名,需要认证操作人
+const ExecSpaceMethod = "/pb.CloudIdeService/execSpace"
+
+// ExecSpace 在工作空间容器中执行命令或打开终端。第一条消息中的start指定工作空间、命令和操作人,
+// 之后转发标准输入和终端大小,命令结束时发送退出码。会话开始和结束都记录审计日志和Pod事件
+func (s *CloudSpaceService) ExecSpace(stream pb.CloudIdeService_ExecSpaceServer) error {
+	if s.executor == nil {
+		return status.Error(codes.FailedPrecondition, ErrExecDisabled.Error())
+	}
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	start := first.Start
+	if start == nil || start.Name == "" || start.Namespace == "" {
+		return status.Error(codes.InvalidArgument, ErrInvalidExec.Error())
+	}
+	if err := s.authorizeExec(stream.Context(), start); err != nil {
+		return err
+	}
+
+	pod, err := s.backend.Get(stream.Context(), start.Namespace, start.Name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return status.Error(codes.NotFound, ErrWorkspaceNotFound.Error())
+		}
+		klog.Errorf("get pod error:%v", err)
+		return status.Error(codes.Unknown, err.Error())
+	}
+	if !workspace.IsPodReady(pod) {
+		return status.Error(codes.FailedPrecondition, ErrWorkspaceNotReady.Error())
+	}
+	command := start.Command
+	if len(command) == 0 {
+		command = []string{s.cfg.Exec.Shell}
+	}
+
+	ctx, cancel := context.WithTimeout(stream.Context(), s.cfg.Exec.MaxDuration.Duration)
+	defer cancel()
+	stdinR, stdinW := io.Pipe()
+	defer stdinR.Close()
+	sizes := &terminalSizeQueue{ctx: ctx, sizes: make(chan remotecommand.TerminalSize, 8)}
+	go s.receiveExecInput(stream, first, stdinW, sizes, cancel)
+	// 超时或调用方断开时关闭标准输入,executor同时关闭与API Server的连接终止会话
+	go func() {
+		<-ctx.Done()
+		stdinW.Close()
+	}()
+
+	out := &execOutput{stream: stream}
+	audit := []interface{}{start.Operator, start.Namespace, start.Name, command, start.Tty, start.Reason}
+	klog.Infof("[ExecSpace] audit: operator=%s workspace=%s/%s command=%q tty=%v reason=%q started", audit...)
+	s.recorder.Eventf(pod, v1.EventTypeNormal, events.ReasonExecStarted, "%s started %q, tty %v, reason %q",
+		start.Operator, command, start.Tty, start.Reason)
+
+	begin := time.Now()
+	code, err := s.executor.Exec(ctx, &podexec.Session{
+		Namespace: pod.Namespace,
+		Pod:       pod.Name,
+		Container: workspace.Container(pod).Name,
+		Command:   command,
+		TTY:       start.Tty,
+		Stdin:     stdinR,
+		Stdout:    out.writer(false),
+		Stderr:    out.writer(true),
+		Resize:    sizes,
+	})
+	duration := time.Since(begin).Round(time.Second)
+	if ctx.Err() != nil {
+		cause, grpcCode := "client disconnected", codes.Canceled
+		if ctx.Err() == context.DeadlineExceeded {
+			cause, grpcCode = fmt.Sprintf("max duration %s exceeded", s.cfg.Exec.MaxDuration.Duration), codes.DeadlineExceeded
+		}
+		klog.Warningf("[ExecSpace] audit: operator=%s workspace=%s/%s command=%q tty=%v reason=%q terminated after %s: %s",
+			append(audit, duration, cause)...)
+		s.recorder.Eventf(pod, v1.EventTypeWarning, events.ReasonExecTerminated, "%s exec %q terminated after %s: %s",
+			start.Operator, command, duration, cause)
+		return status.Error(grpcCode, ErrExecTerminated.Error()+": "+cause)
+	}
+	if err != nil {
+		klog.Errorf("[ExecSpace] audit: operator=%s workspace=%s/%s command=%q tty=%v reason=%q failed after %s: %v",
+			append(audit, duration, err)...)
+		s.recorder.Eventf(pod, v1.EventTypeWarning, events.ReasonExecFinished, "%s exec %q failed after %s: %v",
+			start.Operator, command, duration, err)
+		return status.Error(codes.Unknown, ErrExec.Error())
+	}
+	klog.Infof("[ExecSpace] audit: operator=%s workspace=%s/%s command=%q tty=%v reason=%q exited with %d after %s",
+		append(audit, code, duration)...)
+	s.recorder.Eventf(pod, v1.EventTypeNormal, events.ReasonExecFinished, "%s exec %q exited with %d after %s",
+		start.Operator, command, code, duration)
+
+	return out.send(&pb.ExecResponse{Exited: true, ExitCode: int32(code)})
+}
+
+// authorizeExec 操作人只能来自认证拦截器,消息中的操作人与认证的操作人不一致时拒绝
+func (s *CloudSpaceService) authorizeExec(ctx context.Context, start *pb.ExecStart) error {
+	operator := middleware.OperatorFromContext(ctx)
+	if operator == "" {
+		return status.Error(codes.Unauthenticated, ErrExecUnauthorized.Error())
+	}
+	if start.Operator != "" && start.Operator != operator {
+		klog.Warningf("[ExecSpace] audit: operator=%s claimed=%s workspace=%s/%s denied", operator, start.Operator, start.Namespace, start.Name)
+		return status.Error(codes.PermissionDenied, ErrExecUnauthorized.Error())
+	}
+	start.Operator = operator
+	if s.cfg.Exec.RequireReason && start.Reason == "" {
+		return status.Error(codes.InvalidArgument, ErrInvalidExec.Error())
+	}
+
+	return nil
+}
+
+// receiveExecInput 转发调用方的标准输入和终端大小,调用方关闭发送端时关闭标准输入,连接断开时结束会话
+func (s *CloudSpaceService) receiveExecInput(stream pb.CloudIdeService_ExecSpaceServer, req *pb.ExecRequest,
+	stdin *io.PipeWriter, sizes *terminalSizeQueue, cancel context.CancelFunc) {
+	defer stdin.Close()
+	for {
+		if len(req.Stdin) > 0 {
+			if _, err := stdin.Write(req.Stdin); err != nil {
+				return
+			}
+		}
+		if req.Resize != nil {
+			sizes.push(req.Resize)
+		}
+		if req.CloseStdin {
+			stdin.Close()
+		}
+
+		var err error
+		req, err = stream.Recv()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			cancel()
+			return
+		}
+	}
+}
+
+// terminalSizeQueue 实现remotecommand.TerminalSizeQueue
+type terminalSizeQueue struct {
+	ctx   context.Context
+	sizes chan remotecommand.TerminalSize
+}
+
+func (q *terminalSizeQueue) push(size *pb.TerminalSize) {
+	select {
+	case q.sizes <- remotecommand.TerminalSize{Width: uint16(size.Width), Height: uint16(size.Height)}:
+	default:
+		// 执行端没有及时读取时丢弃,之后的大小变化会覆盖
+	}
+}
+
+// Next 返回nil时结束监听终端大小
+func (q *terminalSizeQueue) Next() *remotecommand.TerminalSize {
+	select {
+	case size := <-q.sizes:
+		return &size
+	case <-q.ctx.Done():
+		return nil
+	}
+}
+
+// execOutput 将标准输出和标准错误发送给调用方,grpc流不能并发发送
+type execOutput struct {
+	mu     sync.Mutex
+	stream pb.CloudIdeService_ExecSpaceServer
+}
+
+func (o *execOutput) send(res *pb.ExecResponse) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.stream.Send(res)
+}
+
+func (o *execOutput) writer(stderr bool) io.Writer {
+	return execWriter{output: o, stderr: stderr}
+}
+
+type execWriter struct {
+	output *execOutput
+	stderr bool
+}
+
+func (w execWriter) Write(p []byte) (int, error) {
+	data := make([]byte, len(p))
+	copy(data, p)
+	res := &pb.ExecResponse{Stdout: data}
+	if w.stderr {
+		res = &pb.ExecResponse{Stderr: data}
+	}
+	if err := w.output.send(res); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/conf"
+	"github.com/mangohow/cloud-ide-k8s-controller/middleware"
+	"github.com/mangohow/cloud-ide-k8s-controller/pb"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeStream 只实现拦截器用到的Context
+type fakeStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeStream) Context() context.Context {
+	return s.ctx
+}
+
+// operatorContext 经过认证拦截器后的上下文,operator为空时不携带认证信息
+func operatorContext(operator string) context.Context {
+	ctx := context.Background()
+	if operator == "" {
+		return ctx
+	}
+	md := metadata.Pairs(middleware.OperatorMetadataKey, operator, middleware.AuthorizationMetadataKey, "Bearer token")
+	stream := &fakeStream{ctx: metadata.NewIncomingContext(ctx, md)}
+	interceptor := middleware.OperatorAuthStreamInterceptorMiddleware(func(context.Context, string, string) error {
+		return nil
+	}, ExecSpaceMethod)
+	err := interceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: ExecSpaceMethod}, func(srv interface{}, ss grpc.ServerStream) error {
+		ctx = ss.Context()
+		return nil
+	})
+	Expect(err).NotTo(HaveOccurred())
+
+	return ctx
+}
+
+var _ = Describe("authorizeExec", func() {
+	DescribeTable("uses only the authenticated operator",
+		func(authenticated, claimed, reason string, requireReason bool, code codes.Code) {
+			s := &CloudSpaceService{cfg: &conf.Config{Exec: conf.ExecConfig{RequireReason: requireReason}}}
+			start := &pb.ExecStart{Name: "ws-1", Namespace: "cloud-ide", Operator: claimed, Reason: reason}
+
+			err := s.authorizeExec(operatorContext(authenticated), start)
+			Expect(status.Code(err)).To(Equal(code))
+			if code == codes.OK {
+				Expect(start.Operator).To(Equal(authenticated))
+			}
+		},
+		Entry("operator filled from the authenticated context", "alice", "", "debug", true, codes.OK),
+		Entry("matching operator", "alice", "alice", "debug", true, codes.OK),
+		Entry("operator mismatch", "alice", "bob", "debug", true, codes.PermissionDenied),
+		Entry("not authenticated", "", "alice", "debug", true, codes.Unauthenticated),
+		Entry("reason required", "alice", "alice", "", true, codes.InvalidArgument),
+		Entry("reason optional", "alice", "alice", "", false, codes.OK),
+	)
+})
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/pb"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/events"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/workspace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// gitCredentialsPath 凭证Secret在init容器中的挂载路径
+const gitCredentialsPath = "/etc/git-credentials"
+
+// gitCloneScript 克隆仓库到存储卷的子目录中,先克隆到临时目录再移动,目标目录已经是仓库时直接成功。
+// 克隆失败时同样以0退出,不影响工作空间启动,结果写入终止消息,以Cloned或Failed开头,
+// 需要从仓库中读取devcontainer.json时,克隆成功后将其追加到终止消息中(终止消息最多4096字节)
+const gitCloneScript = `set -u
+DEST="$GIT_TARGET/$GIT_DIRECTORY"
+TMP="$GIT_TARGET/.git-clone-tmp"
+fail() {
+  rm -rf "$TMP"
+  echo "Failed: $1" | tee /dev/termination-log
+  exit 0
+}
+devcontainer() {
+  if [ -n "$GIT_DEVCONTAINER" ] && [ -f "$DEST/$GIT_DEVCONTAINER" ]; then
+    head -c 3072 "$DEST/$GIT_DEVCONTAINER" >> /dev/termination-log
+  fi
+}
+if [ -d "$DEST/.git" ]; then
+  echo "Cloned: $DEST already exists" | tee /dev/termination-log
+  devcontainer
+  exit 0
+fi
+if [ -n "$(ls -A "$DEST" 2>/dev/null)" ]; then
+  fail "$DEST is not empty"
+fi
+export HOME=/tmp
+CRED=` + gitCredentialsPath + `
+if [ -f "$CRED/ssh-privatekey" ]; then
+  cp "$CRED/ssh-privatekey" /tmp/id_git && chmod 600 /tmp/id_git
+  HOSTS="-o StrictHostKeyChecking=accept-new"
+  if [ -f "$CRED/known_hosts" ]; then
+    HOSTS="-o UserKnownHostsFile=$CRED/known_hosts"
+  fi
+  export GIT_SSH_COMMAND="ssh -i /tmp/id_git $HOSTS"
+fi
+if [ -f "$CRED/username" ]; then
+  git config --global credential.helper '!f() { echo "username=$(cat '"$CRED"'/username)"; echo "password=$(cat '"$CRED"'/password)"; }; f'
+fi
+rm -rf "$TMP"
+OUT=$(git clone --quiet -- "$GIT_URL" "$TMP" 2>&1) || fail "clone $GIT_URL: $(echo "$OUT" | tail -n 3)"
+if [ -n "$GIT_REF" ]; then
+  OUT=$(git -C "$TMP" checkout --quiet "$GIT_REF" 2>&1) || fail "checkout $GIT_REF: $(echo "$OUT" | tail -n 3)"
+fi
+mkdir -p "$(dirname "$DEST")"
+rmdir "$DEST" 2>/dev/null
+mv "$TMP" "$DEST" || fail "move repository to $DEST"
+echo "Cloned: $GIT_URL at $(git -C "$DEST" rev-parse HEAD)" | tee /dev/termination-log
+devcontainer`
+
+// gitSource 校验调用方指定的Git源,没有指定时返回nil,子目录为空时使用仓库名
+func (s *CloudSpaceService) gitSource(ctx context.Context, info *pb.WorkspaceInfo) (*workspace.GitSource, error) {
+	git := info.Git
+	if git == nil || git.Url == "" {
+		return nil, nil
+	}
+
+	// 以-开头的地址和版本会被git当作选项,本地路径和file://会读取init容器中的文件
+	if !validGitURL(git.Url) || strings.HasPrefix(git.Ref, "-") {
+		return nil, status.Error(codes.InvalidArgument, ErrInvalidGitSource.Error())
+	}
+	dir := git.Subdirectory
+	if dir == "" {
+		dir = repoName(git.Url)
+	}
+	dir = path.Clean(dir)
+	if path.IsAbs(dir) || dir == "." || dir == ".." || strings.HasPrefix(dir, "../") {
+		return nil, status.Error(codes.InvalidArgument, ErrInvalidGitSource.Error())
+	}
+	if git.SecretName != "" {
+		secret := &v1.Secret{}
+		err := s.client.Get(ctx, client.ObjectKey{Name: git.SecretName, Namespace: info.Namespace}, secret)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return nil, status.Error(codes.InvalidArgument, ErrGitSecretNotFound.Error())
+			}
+			klog.Errorf("get git secret error:%v", err)
+			return nil, status.Error(codes.Unknown, err.Error())
+		}
+		if !gitSecretAllowed(secret, info.Owner) {
+			return nil, status.Error(codes.InvalidArgument, ErrGitSecretNotAllowed.Error())
+		}
+	}
+
+	return &workspace.GitSource{
+		URL:        git.Url,
+		Ref:        git.Ref,
+		Directory:  dir,
+		SecretName: git.SecretName,
+	}, nil
+}
+
+// scpURL scp形式的ssh地址,例如git@github.com:owner/repo.git
+var scpURL = regexp.MustCompile(`^[A-Za-z0-9._~-]+@[A-Za-z0-9.-]+:[^-/\s][^\s]*$`)
+
+// validGitURL 只允许https、ssh和scp形式的ssh地址
+func validGitURL(s string) bool {
+	if strings.HasPrefix(s, "https://") || strings.HasPrefix(s, "ssh://") {
+		u, err := url.Parse(s)
+		return err == nil && u.Host != "" && !strings.HasPrefix(u.Host, "-") && !strings.ContainsAny(s, " \t\r\n")
+	}
+
+	return scpURL.MatchString(s)
+}
+
+// gitSecretAllowed 凭证会被发送到调用方指定的地址,只能使用工作空间所有者自己的凭证
+func gitSecretAllowed(secret *v1.Secret, owner string) bool {
+	return owner != "" && secret.Labels[workspace.LabelKind] == workspace.KindGitCredentials &&
+		secret.Labels[workspace.LabelOwner] == owner
+}
+
+// repoName 从仓库地址中取出仓库名,支持https和scp形式的ssh地址
+func repoName(url string) string {
+	url = strings.TrimSuffix(strings.TrimRight(url, "/"), ".git")
+	if i := strings.LastIndexAny(url, "/:"); i >= 0 {
+		url = url[i+1:]
+	}
+	if url == "" {
+		return "repo"
+	}
+
+	return url
+}
+
+// applyGitClone 还没有克隆成功的工作空间添加克隆仓库的init容器,
+// 每次启动都重新检查凭证Secret的标签,创建后被修改或替换的Secret不再挂载
+func (s *CloudSpaceService) applyGitClone(ctx context.Context, pod *v1.Pod, pvc *v1.PersistentVolumeClaim) {
+	src := workspace.GetGitSource(pvc)
+	if src == nil || workspace.GitCloned(pvc) {
+		return
+	}
+	if src.SecretName != "" {
+		secret := &v1.Secret{}
+		err := s.client.Get(ctx, client.ObjectKey{Name: src.SecretName, Namespace: pvc.Namespace}, secret)
+		if err == nil && !gitSecretAllowed(secret, pvc.Labels[workspace.LabelOwner]) {
+			err = ErrGitSecretNotAllowed
+		}
+		if err != nil {
+			klog.Warningf("git credentials secret %s not mounted, workspace:%s, err:%v", src.SecretName, pvc.Name, err)
+			s.recorder.Eventf(pvc, v1.EventTypeWarning, events.ReasonGitCloneFailed, "git credentials secret %s not mounted: %v", src.SecretName, err)
+			src.SecretName = ""
+		}
+	}
+
+	container := v1.Container{
+		Name:            workspace.GitCloneContainer,
+		Image:           s.cfg.Git.Image,
+		ImagePullPolicy: v1.PullIfNotPresent,
+		Command:         []string{"sh", "-c", gitCloneScript},
+		Env: []v1.EnvVar{
+			{Name: "GIT_URL", Value: src.URL},
+			{Name: "GIT_REF", Value: src.Ref},
+			{Name: "GIT_TARGET", Value: strings.TrimSuffix(workspaceMountPath, "/")},
+			{Name: "GIT_DIRECTORY", Value: src.Directory},
+			{Name: "GIT_DEVCONTAINER", Value: pvc.Annotations[workspace.AnnotationDevcontainerPath]},
+		},
+		VolumeMounts: []v1.VolumeMount{
+			{Name: workspaceVolume, MountPath: workspaceMountPath},
+		},
+		TerminationMessagePolicy: v1.TerminationMessageReadFile,
+	}
+	if src.SecretName != "" {
+		mode := int32(0400)
+		pod.Spec.Volumes = append(pod.Spec.Volumes, v1.Volume{
+			Name: "git-credentials",
+			VolumeSource: v1.VolumeSource{
+				Secret: &v1.SecretVolumeSource{SecretName: src.SecretName, DefaultMode: &mode},
+			},
+		})
+		container.VolumeMounts = append(container.VolumeMounts, v1.VolumeMount{
+			Name:      "git-credentials",
+			MountPath: gitCredentialsPath,
+			ReadOnly:  true,
+		})
+	}
+	pod.Spec.InitContainers = append(pod.Spec.InitContainers, container)
+}
+
+// gitConditions 返回PVC上记录的克隆结果
+func gitConditions(pvc *v1.PersistentVolumeClaim) []*pb.WorkspaceCondition {
+	cond := workspace.GitCondition(pvc)
+	if cond == nil {
+		return nil
+	}
+
+	return []*pb.WorkspaceCondition{{
+		Type:               cond.Type,
+		Status:             string(cond.Status),
+		Reason:             cond.Reason,
+		Message:            cond.Message,
+		LastTransitionTime: cond.LastTransitionTime.Unix(),
+	}}
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// GetImageCacheStatus 获取预拉取镜像在每个节点上的拉取状态,未启用预拉取时返回空列表
+func (s *CloudSpaceService) GetImageCacheStatus(ctx context.Context, query *pb.ImageCacheQuery) (*pb.ImageCacheStatus, error) {
+	nodes, err := s.puller.Status(ctx, query.Image)
+	if err != nil {
+		klog.Errorf("get image cache status error:%v", err)
+		return &pb.ImageCacheStatus{}, status.Error(codes.Unknown, ErrGetImageCacheStatus.Error())
+	}
+
+	res := &pb.ImageCacheStatus{}
+	for _, node := range nodes {
+		n := &pb.NodeImageCache{NodeName: node.NodeName}
+		for _, image := range node.Images {
+			n.Images = append(n.Images, &pb.ImagePullStatus{
+				Image:   image.Image,
+				State:   image.State,
+				Message: image.Message,
+			})
+		}
+		res.Nodes = append(res.Nodes, n)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,305 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/pb"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/workspace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// validatePorts 校验WorkspaceInfo中的具名端口,名称和端口都不能重复,也不能使用工作空间的端口
+func validatePorts(info *pb.WorkspaceInfo) error {
+	names := make(map[string]bool)
+	ports := map[int32]bool{info.Port: true}
+	for _, p := range info.Ports {
+		if len(validation.IsValidPortName(p.Name)) > 0 || names[p.Name] {
+			return status.Errorf(codes.InvalidArgument, "%s: invalid port name %q", ErrInvalidPort.Error(), p.Name)
+		}
+		if len(validation.IsValidPortNum(int(p.Port))) > 0 || ports[p.Port] {
+			return status.Errorf(codes.InvalidArgument, "%s: invalid port %d", ErrInvalidPort.Error(), p.Port)
+		}
+		names[p.Name] = true
+		ports[p.Port] = true
+	}
+
+	return nil
+}
+
+// ExposePort 暴露工作空间中的端口,生成预览地址,已经暴露时更新名称和可见性,预览地址不变
+func (s *CloudSpaceService) ExposePort(ctx context.Context, req *pb.ExposeRequest) (*pb.ExposedPort, error) {
+	cfg := s.cfg.Preview
+	if !cfg.Enabled {
+		return &pb.ExposedPort{}, status.Error(codes.FailedPrecondition, ErrPreviewDisabled.Error())
+	}
+	visibility := req.Visibility
+	if visibility == "" {
+		visibility = workspace.VisibilityPrivate
+	}
+	if visibility != workspace.VisibilityPrivate && visibility != workspace.VisibilityShared {
+		return &pb.ExposedPort{}, status.Errorf(codes.InvalidArgument, "%s: invalid visibility %q", ErrInvalidPort.Error(), visibility)
+	}
+	// 没有配置认证时不能创建私有预览,否则私有地址可以被任何人访问
+	if visibility == workspace.VisibilityPrivate && len(cfg.PrivateAnnotations) == 0 {
+		return &pb.ExposedPort{}, status.Errorf(codes.FailedPrecondition, "%s: private previews require privateAnnotations", ErrExposePort.Error())
+	}
+	pvc, err := s.previewPVC(ctx, req)
+	if err != nil {
+		return &pb.ExposedPort{}, err
+	}
+	port, name, err := s.resolvePort(ctx, req)
+	if err != nil {
+		return &pb.ExposedPort{}, err
+	}
+
+	// 保持已有的预览地址
+	existing := &networkingv1.Ingress{}
+	err = s.client.Get(ctx, client.ObjectKey{Namespace: pvc.Namespace, Name: workspace.PreviewIngressName(pvc.Name, port)}, existing)
+	if err != nil && !errors.IsNotFound(err) {
+		klog.Errorf("get preview ingress error:%v", err)
+		return &pb.ExposedPort{}, status.Error(codes.Unknown, ErrExposePort.Error())
+	}
+	exists := err == nil
+	host := ""
+	if exists && len(existing.Spec.Rules) > 0 {
+		host = existing.Spec.Rules[0].Host
+	} else {
+		token := make([]byte, 8)
+		if _, err := rand.Read(token); err != nil {
+			return &pb.ExposedPort{}, status.Error(codes.Unknown, ErrExposePort.Error())
+		}
+		host = fmt.Sprintf("%d-%s.%s", port, hex.EncodeToString(token), cfg.Domain)
+	}
+
+	ing := workspace.PreviewIngress(pvc, port, host, cfg.IngressClassName, cfg.TLSSecretName)
+	annotations := cfg.PrivateAnnotations
+	if visibility == workspace.VisibilityShared {
+		annotations = cfg.SharedAnnotations
+	}
+	for k, v := range annotations {
+		ing.Annotations[k] = v
+	}
+	ing.Annotations[workspace.AnnotationPreviewName] = name
+	ing.Annotations[workspace.AnnotationPreviewVisibility] = visibility
+	if exists {
+		ing.ResourceVersion = existing.ResourceVersion
+		err = s.client.Update(ctx, ing)
+	} else {
+		err = s.client.Create(ctx, ing)
+	}
+	if err != nil {
+		klog.Errorf("save preview ingress error:%v", err)
+		if errors.IsConflict(err) || errors.IsAlreadyExists(err) {
+			return &pb.ExposedPort{}, status.Error(codes.Aborted, ErrExposePort.Error())
+		}
+		return &pb.ExposedPort{}, status.Error(codes.Unknown, ErrExposePort.Error())
+	}
+	if err := s.syncPreviewService(ctx, pvc); err != nil {
+		return &pb.ExposedPort{}, err
+	}
+	klog.Infof("[ExposePort] exposed port %d of %s as %s", port, pvc.Name, visibility)
+
+	return s.exposedPort(ing), nil
+}
+
+// UnexposePort 取消暴露端口,端口没有暴露时同样返回成功
+func (s *CloudSpaceService) UnexposePort(ctx context.Context, req *pb.ExposeRequest) (*pb.Response, error) {
+	pvc, err := s.previewPVC(ctx, req)
+	if err != nil {
+		return ResponseFailed, err
+	}
+	port := req.Port
+	if port == 0 {
+		// 按名称查找已经暴露的端口
+		ingresses, err := workspace.ListPreviews(ctx, s.client, pvc.Namespace, pvc.Name)
+		if err != nil {
+			klog.Errorf("list preview ingresses error:%v", err)
+			return ResponseFailed, status.Error(codes.Unknown, ErrUnexposePort.Error())
+		}
+		for i := range ingresses {
+			if req.PortName != "" && ingresses[i].Annotations[workspace.AnnotationPreviewName] == req.PortName {
+				port = workspace.PreviewPort(&ingresses[i])
+			}
+		}
+		if port == 0 {
+			return ResponseSuccess, nil
+		}
+	}
+
+	ing := &networkingv1.Ingress{}
+	ing.Name, ing.Namespace = workspace.PreviewIngressName(pvc.Name, port), pvc.Namespace
+	if err := s.client.Delete(ctx, ing); err != nil && !errors.IsNotFound(err) {
+		klog.Errorf("delete preview ingress error:%v", err)
+		return ResponseFailed, status.Error(codes.Unknown, ErrUnexposePort.Error())
+	}
+	if err := s.syncPreviewService(ctx, pvc); err != nil {
+		return ResponseFailed, err
+	}
+	klog.Infof("[UnexposePort] unexposed port %d of %s", port, pvc.Name)
+
+	return ResponseSuccess, nil
+}
+
+// previewPVC 返回可以暴露端口的工作空间的PVC
+func (s *CloudSpaceService) previewPVC(ctx context.Context, req *pb.ExposeRequest) (*v1.PersistentVolumeClaim, error) {
+	pvc := &v1.PersistentVolumeClaim{}
+	if err := s.client.Get(ctx, client.ObjectKey{Name: req.Name, Namespace: req.Namespace}, pvc); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, status.Error(codes.NotFound, ErrWorkspaceNotFound.Error())
+		}
+		klog.Errorf("get pvc error:%v", err)
+		return nil, status.Error(codes.Unknown, err.Error())
+	}
+	if pvc.DeletionTimestamp != nil {
+		return nil, status.Error(codes.FailedPrecondition, ErrWorkspaceDeleting.Error())
+	}
+	if pvc.Labels[workspace.LabelState] == workspace.StateTrashed {
+		return nil, status.Error(codes.FailedPrecondition, ErrWorkspaceTrashed.Error())
+	}
+
+	return pvc, nil
+}
+
+// resolvePort 根据运行中的工作空间解析端口和名称,不能暴露code-server的端口。
+// 工作空间的端口只有运行时才能确定,因此只能暴露运行中的工作空间的端口
+func (s *CloudSpaceService) resolvePort(ctx context.Context, req *pb.ExposeRequest) (int32, string, error) {
+	port, name := req.Port, req.PortName
+	if port == 0 && name == "" {
+		return 0, "", status.Errorf(codes.InvalidArgument, "%s: port or portName is required", ErrInvalidPort.Error())
+	}
+	pod, err := s.backend.Get(ctx, req.Namespace, req.Name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return 0, "", status.Errorf(codes.FailedPrecondition, "%s: the workspace is not running", ErrInvalidPort.Error())
+		}
+		klog.Errorf("get pod error:%v", err)
+		return 0, "", status.Error(codes.Unknown, ErrExposePort.Error())
+	}
+	for _, p := range workspace.Container(pod).Ports {
+		switch {
+		case port == 0 && p.Name == name:
+			port = p.ContainerPort
+		case name == "" && p.ContainerPort == port:
+			name = p.Name
+		}
+	}
+	if port == 0 {
+		return 0, "", status.Errorf(codes.InvalidArgument, "%s: port %q not found", ErrInvalidPort.Error(), name)
+	}
+	if port == workspacePort(pod) {
+		return 0, "", status.Errorf(codes.InvalidArgument, "%s: the workspace port can not be exposed", ErrInvalidPort.Error())
+	}
+	if len(validation.IsValidPortNum(int(port))) > 0 {
+		return 0, "", status.Errorf(codes.InvalidArgument, "%s: invalid port %d", ErrInvalidPort.Error(), port)
+	}
+
+	return port, name, nil
+}
+
+// workspacePort 返回Pod中code-server的端口,即工作空间容器的第一个端口
+func workspacePort(pod *v1.Pod) int32 {
+	if ports := workspace.Container(pod).Ports; len(ports) > 0 {
+		return ports[0].ContainerPort
+	}
+
+	return 0
+}
+
+// unexposeWorkspacePort 启动时工作空间的端口可能与之前暴露的端口相同,删除该端口的预览,
+// 避免持有预览地址的人访问到code-server
+func (s *CloudSpaceService) unexposeWorkspacePort(ctx context.Context, pvc *v1.PersistentVolumeClaim, port int32) error {
+	if !s.cfg.Preview.Enabled {
+		return nil
+	}
+	ing := &networkingv1.Ingress{}
+	ing.Name, ing.Namespace = workspace.PreviewIngressName(pvc.Name, port), pvc.Namespace
+	if err := s.client.Delete(ctx, ing); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	klog.Warningf("workspace port %d of %s was exposed, preview removed", port, pvc.Name)
+
+	return s.syncPreviewService(ctx, pvc)
+}
+
+// removePreviews 删除工作空间的所有预览Ingress和Service
+func (s *CloudSpaceService) removePreviews(ctx context.Context, pvc *v1.PersistentVolumeClaim) error {
+	ingresses, err := workspace.ListPreviews(ctx, s.apiReader, pvc.Namespace, pvc.Name)
+	if err != nil {
+		return err
+	}
+	for i := range ingresses {
+		if err := s.client.Delete(ctx, &ingresses[i]); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return workspace.SyncPreviewService(ctx, s.client, pvc, nil)
+}
+
+// syncPreviewService 根据已经暴露的端口更新预览Service
+func (s *CloudSpaceService) syncPreviewService(ctx context.Context, pvc *v1.PersistentVolumeClaim) error {
+	// 直接读取API Server,缓存中可能还没有刚刚修改的Ingress
+	ingresses, err := workspace.ListPreviews(ctx, s.apiReader, pvc.Namespace, pvc.Name)
+	if err != nil {
+		klog.Errorf("list preview ingresses error:%v", err)
+		return status.Error(codes.Unknown, ErrExposePort.Error())
+	}
+	var ports []int32
+	for i := range ingresses {
+		ports = append(ports, workspace.PreviewPort(&ingresses[i]))
+	}
+	if err := workspace.SyncPreviewService(ctx, s.client, pvc, ports); err != nil {
+		klog.Errorf("sync preview service error:%v", err)
+		return status.Error(codes.Unknown, ErrExposePort.Error())
+	}
+
+	return nil
+}
+
+// exposedPorts 返回工作空间暴露的端口,用于工作空间信息,出错时返回nil
+func (s *CloudSpaceService) exposedPorts(ctx context.Context, namespace, name string) []*pb.ExposedPort {
+	if !s.cfg.Preview.Enabled {
+		return nil
+	}
+	ingresses, err := workspace.ListPreviews(ctx, s.client, namespace, name)
+	if err != nil {
+		klog.Errorf("list preview ingresses error:%v", err)
+		return nil
+	}
+	res := make([]*pb.ExposedPort, 0, len(ingresses))
+	for i := range ingresses {
+		res = append(res, s.exposedPort(&ingresses[i]))
+	}
+
+	return res
+}
+
+func (s *CloudSpaceService) exposedPort(ing *networkingv1.Ingress) *pb.ExposedPort {
+	res := &pb.ExposedPort{
+		Port:       workspace.PreviewPort(ing),
+		Name:       ing.Annotations[workspace.AnnotationPreviewName],
+		Visibility: ing.Annotations[workspace.AnnotationPreviewVisibility],
+	}
+	if len(ing.Spec.Rules) > 0 {
+		scheme := "http"
+		if len(ing.Spec.TLS) > 0 {
+			scheme = "https"
+		}
+		res.Url = fmt.Sprintf("%s://%s/", scheme, ing.Spec.Rules[0].Host)
+	}
+
+	return res
+}
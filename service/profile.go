@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/pb"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/workspace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// maxProfileSize 用户配置中所有文件的总大小上限,Secret最大为1MiB
+	maxProfileSize = 512 * 1024
+	// profileMountPath 用户配置Secret在init容器中的挂载路径
+	profileMountPath = "/etc/cloud-ide-profile"
+	profileVolume    = "user-profile"
+	profileContainer = "profile"
+)
+
+// profileScript 将用户配置中的文件复制到存储卷中,覆盖已有的同名文件,复制后的文件属于存储卷根目录的所有者。
+// Secret卷中以..开头的是kubelet使用的目录,跳过
+const profileScript = `set -e
+OWNER=$(stat -c %u:%g "$PROFILE_VOLUME")
+mkdir -p "$PROFILE_TARGET"
+cd ` + profileMountPath + `
+for f in * .[!.]*; do
+  [ -e "$f" ] || continue
+  cp -rL "$f" "$PROFILE_TARGET"/
+  chown -R "$OWNER" "$PROFILE_TARGET/$f"
+done
+echo "user profile synced to $PROFILE_TARGET"`
+
+// validateOwner 用户名保存在标签中,需要是合法的标签值
+func validateOwner(owner string) error {
+	if owner == "" || len(validation.IsValidLabelValue(owner)) > 0 {
+		return status.Error(codes.InvalidArgument, ErrInvalidOwner.Error())
+	}
+
+	return nil
+}
+
+// GetUserProfile 获取用户配置,没有配置时返回空的文件列表
+func (s *CloudSpaceService) GetUserProfile(ctx context.Context, query *pb.ProfileQuery) (*pb.UserProfile, error) {
+	if err := validateOwner(query.Owner); err != nil {
+		return &pb.UserProfile{}, err
+	}
+	res := &pb.UserProfile{Owner: query.Owner, Namespace: query.Namespace}
+	// 直接读取API Server,更新后立即读取时缓存可能还没有同步
+	secret := &v1.Secret{}
+	err := s.apiReader.Get(ctx, client.ObjectKey{Name: workspace.ProfileSecretName(query.Owner), Namespace: query.Namespace}, secret)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return res, nil
+		}
+		klog.Errorf("get user profile error:%v", err)
+		return &pb.UserProfile{}, status.Error(codes.Unknown, ErrGetProfile.Error())
+	}
+
+	for _, key := range sortedSecretKeys(secret) {
+		p, ok := workspace.ProfilePath(key)
+		if !ok {
+			continue
+		}
+		res.Files = append(res.Files, &pb.ProfileFile{Path: p, Content: secret.Data[key]})
+	}
+	if t, err := time.Parse(time.RFC3339, secret.Annotations[workspace.AnnotationProfileUpdatedAt]); err == nil {
+		res.UpdatedAt = t.Unix()
+	}
+
+	return res, nil
+}
+
+// UpdateUserProfile 替换用户配置中的所有文件,文件列表为空时删除配置,下次启动工作空间时生效
+func (s *CloudSpaceService) UpdateUserProfile(ctx context.Context, profile *pb.UserProfile) (*pb.Response, error) {
+	if err := validateOwner(profile.Owner); err != nil {
+		return ResponseFailed, err
+	}
+	data := make(map[string][]byte, len(profile.Files))
+	size := 0
+	for _, f := range profile.Files {
+		// 以..开头的路径与Secret卷中kubelet使用的目录冲突
+		p := path.Clean(f.Path)
+		key := workspace.ProfileKey(p)
+		if f.Path == "" || path.IsAbs(p) || p == "." || strings.HasPrefix(p, "..") || len(validation.IsConfigMapKey(key)) > 0 {
+			return ResponseFailed, status.Errorf(codes.InvalidArgument, "%s: invalid path %q", ErrInvalidProfile.Error(), f.Path)
+		}
+		size += len(f.Content)
+		data[key] = f.Content
+	}
+	if size > maxProfileSize {
+		return ResponseFailed, status.Errorf(codes.InvalidArgument, "%s: profile is larger than %d bytes", ErrInvalidProfile.Error(), maxProfileSize)
+	}
+
+	key := client.ObjectKey{Name: workspace.ProfileSecretName(profile.Owner), Namespace: profile.Namespace}
+	secret := &v1.Secret{}
+	err := s.apiReader.Get(ctx, key, secret)
+	if err != nil && !errors.IsNotFound(err) {
+		klog.Errorf("get user profile error:%v", err)
+		return ResponseFailed, status.Error(codes.Unknown, ErrUpdateProfile.Error())
+	}
+	exists := err == nil
+
+	if len(data) == 0 {
+		if exists {
+			if err := s.client.Delete(ctx, secret); err != nil && !errors.IsNotFound(err) {
+				klog.Errorf("delete user profile error:%v", err)
+				return ResponseFailed, status.Error(codes.Unknown, ErrUpdateProfile.Error())
+			}
+		}
+		return ResponseSuccess, nil
+	}
+
+	if !exists {
+		secret = &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+				Labels: map[string]string{
+					workspace.LabelKind:  workspace.KindProfile,
+					workspace.LabelOwner: profile.Owner,
+				},
+			},
+		}
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations[workspace.AnnotationProfileUpdatedAt] = time.Now().UTC().Format(time.RFC3339)
+	secret.Data = data
+	if exists {
+		err = s.client.Update(ctx, secret)
+	} else {
+		err = s.client.Create(ctx, secret)
+	}
+	if err != nil {
+		klog.Errorf("update user profile error:%v", err)
+		if errors.IsConflict(err) || errors.IsAlreadyExists(err) {
+			return ResponseFailed, status.Error(codes.Aborted, ErrUpdateProfile.Error())
+		}
+		return ResponseFailed, status.Error(codes.Unknown, ErrUpdateProfile.Error())
+	}
+	klog.Infof("[UpdateUserProfile] updated profile of %s, %d files", profile.Owner, len(data))
+
+	return ResponseSuccess, nil
+}
+
+// applyProfile 添加同步用户配置的init容器,读取配置失败时不影响启动
+func (s *CloudSpaceService) applyProfile(ctx context.Context, pod *v1.Pod, pvc *v1.PersistentVolumeClaim) {
+	owner := pvc.Labels[workspace.LabelOwner]
+	if owner == "" {
+		return
+	}
+	secret := &v1.Secret{}
+	err := s.client.Get(ctx, client.ObjectKey{Name: workspace.ProfileSecretName(owner), Namespace: pvc.Namespace}, secret)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			klog.Warningf("get user profile error:%v, skip syncing profile of %s", err, owner)
+		}
+		return
+	}
+
+	var items []v1.KeyToPath
+	for _, key := range sortedSecretKeys(secret) {
+		if p, ok := workspace.ProfilePath(key); ok {
+			items = append(items, v1.KeyToPath{Key: key, Path: p})
+		}
+	}
+	if len(items) == 0 {
+		return
+	}
+
+	// 配置被删除后重新创建Pod(例如自动恢复)时跳过同步
+	optional := true
+	pod.Spec.Volumes = append(pod.Spec.Volumes, v1.Volume{
+		Name: profileVolume,
+		VolumeSource: v1.VolumeSource{
+			Secret: &v1.SecretVolumeSource{SecretName: secret.Name, Items: items, Optional: &optional},
+		},
+	})
+	volumeRoot := strings.TrimSuffix(workspaceMountPath, "/")
+	pod.Spec.InitContainers = append(pod.Spec.InitContainers, v1.Container{
+		Name:            profileContainer,
+		Image:           s.cfg.Profile.Image,
+		ImagePullPolicy: v1.PullIfNotPresent,
+		Command:         []string{"sh", "-c", profileScript},
+		Env: []v1.EnvVar{
+			{Name: "PROFILE_VOLUME", Value: volumeRoot},
+			{Name: "PROFILE_TARGET", Value: path.Join(volumeRoot, s.cfg.Profile.Dir)},
+		},
+		VolumeMounts: []v1.VolumeMount{
+			{Name: workspaceVolume, MountPath: workspaceMountPath},
+			{Name: profileVolume, MountPath: profileMountPath, ReadOnly: true},
+		},
+	})
+}
+
+func sortedSecretKeys(secret *v1.Secret) []string {
+	keys := make([]string, 0, len(secret.Data))
+	for key := range secret.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
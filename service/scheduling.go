@@ -0,0 +1,57 @@
+package service
+
+import (
+	"github.com/mangohow/cloud-ide-k8s-controller/conf"
+	"github.com/mangohow/cloud-ide-k8s-controller/pb"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/workspace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// schedulingProfile 返回工作空间使用的调度配置,没有指定且没有默认配置时返回nil
+func (s *CloudSpaceService) schedulingProfile(info *pb.WorkspaceInfo) (*conf.SchedulingProfile, error) {
+	name := info.SchedulingProfile
+	if name == "" {
+		name = s.cfg.Scheduling.DefaultProfile
+	}
+	if name == "" {
+		return nil, nil
+	}
+	profile, ok := s.cfg.Scheduling.Profiles[name]
+	if !ok {
+		klog.Warningf("scheduling profile not found, profile:%s", name)
+		return nil, status.Error(codes.InvalidArgument, ErrSchedulingProfile.Error())
+	}
+
+	return &profile, nil
+}
+
+// applySchedulingProfile 将调度配置设置到Pod上,配置在所有工作空间之间共享,因此需要深拷贝
+func applySchedulingProfile(pod *v1.Pod, profile *conf.SchedulingProfile) {
+	if profile == nil {
+		return
+	}
+	if len(profile.NodeSelector) > 0 {
+		pod.Spec.NodeSelector = make(map[string]string, len(profile.NodeSelector))
+		for k, v := range profile.NodeSelector {
+			pod.Spec.NodeSelector[k] = v
+		}
+	}
+	if profile.Affinity != nil {
+		pod.Spec.Affinity = profile.Affinity.DeepCopy()
+	}
+	for i := range profile.Tolerations {
+		pod.Spec.Tolerations = append(pod.Spec.Tolerations, *profile.Tolerations[i].DeepCopy())
+	}
+	for i := range profile.TopologySpreadConstraints {
+		c := profile.TopologySpreadConstraints[i].DeepCopy()
+		if c.LabelSelector == nil {
+			c.LabelSelector = &metav1.LabelSelector{MatchLabels: map[string]string{workspace.LabelKind: workspace.KindCloudIde}}
+		}
+		pod.Spec.TopologySpreadConstraints = append(pod.Spec.TopologySpreadConstraints, *c)
+	}
+	pod.Spec.PriorityClassName = profile.PriorityClassName
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// seedScript 存储卷中没有标记文件时复制种子目录,不覆盖已有文件,复制完成后写入标记文件,
+// 复制中途失败时重新启动会继续复制;种子目录不存在时不写入标记文件,修正配置后下次启动仍会复制
+const seedScript = `set -e
+if [ -f "$SEED_TARGET/$SEED_MARKER" ]; then
+  echo "workspace already seeded"
+  exit 0
+fi
+if [ ! -d "$SEED_SOURCE" ]; then
+  echo "seed directory $SEED_SOURCE not found, skip"
+  exit 0
+fi
+cp -an "$SEED_SOURCE"/. "$SEED_TARGET"/
+touch "$SEED_TARGET/$SEED_MARKER"
+echo "workspace seeded from $SEED_SOURCE"`
+
+// applySeed 添加初始化存储卷的init容器,镜像目录中的配置优先于全局配置
+func (s *CloudSpaceService) applySeed(ctx context.Context, pod *v1.Pod, image string) {
+	dir, seedImage := s.cfg.Seed.Dir, s.cfg.Seed.Image
+	img, ok, err := s.catalog.Find(ctx, image)
+	if err != nil {
+		klog.Warningf("find catalog image error:%v, use default seed config", err)
+	}
+	if ok {
+		if img.SeedDir != "" {
+			dir = img.SeedDir
+		}
+		if img.SeedImage != "" {
+			seedImage = img.SeedImage
+		}
+	}
+	if dir == "" {
+		return
+	}
+	if seedImage == "" {
+		seedImage = image
+	}
+
+	pod.Spec.InitContainers = append(pod.Spec.InitContainers, v1.Container{
+		Name:            "seed",
+		Image:           seedImage,
+		ImagePullPolicy: v1.PullIfNotPresent,
+		Command:         []string{"sh", "-c", seedScript},
+		Env: []v1.EnvVar{
+			{Name: "SEED_SOURCE", Value: dir},
+			{Name: "SEED_TARGET", Value: workspaceMountPath},
+			{Name: "SEED_MARKER", Value: s.cfg.Seed.Marker},
+		},
+		VolumeMounts: []v1.VolumeMount{
+			{Name: workspaceVolume, MountPath: workspaceMountPath},
+		},
+	})
+}
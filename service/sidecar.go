@@ -0,0 +1,133 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/pb"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/workspace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// sidecarsDir 存储卷中保存附加服务数据的目录
+const sidecarsDir = ".sidecars"
+
+// sidecarContainers 根据附加服务目录构造附加服务的容器,请求的服务不在目录中或与工作空间冲突时返回错误
+func (s *CloudSpaceService) sidecarContainers(info *pb.WorkspaceInfo) ([]v1.Container, error) {
+	invalid := func(format string, args ...interface{}) error {
+		return status.Errorf(codes.InvalidArgument, "%s: %s", ErrInvalidSidecar.Error(), fmt.Sprintf(format, args...))
+	}
+
+	// 工作空间的容器和init容器的名称,以及共享网络中已经使用的端口
+	names := map[string]bool{info.Name: true, "seed": true, workspace.GitCloneContainer: true, profileContainer: true, sshContainer: true}
+	ports := map[int32]string{info.Port: info.Name}
+	for _, p := range info.Ports {
+		ports[p.Port] = info.Name
+	}
+	containers := make([]v1.Container, 0, len(info.Sidecars))
+	for _, sc := range info.Sidecars {
+		if errs := validation.IsDNS1123Label(sc.Name); len(errs) > 0 {
+			return nil, invalid("invalid name %q", sc.Name)
+		}
+		if names[sc.Name] {
+			return nil, invalid("duplicate name %q", sc.Name)
+		}
+		names[sc.Name] = true
+		tpl, ok := s.cfg.Sidecars.Catalog[sc.CatalogId]
+		if !ok {
+			return nil, invalid("%q is not in the sidecar catalog", sc.CatalogId)
+		}
+
+		container := v1.Container{
+			Name:            sc.Name,
+			Image:           tpl.Image,
+			ImagePullPolicy: v1.PullIfNotPresent,
+			Args:            append([]string(nil), tpl.Args...),
+			ReadinessProbe:  tpl.ReadinessProbe.DeepCopy(),
+		}
+		for _, port := range tpl.Ports {
+			if owner, ok := ports[port]; ok {
+				return nil, invalid("port %d of %q is already used by %q", port, sc.Name, owner)
+			}
+			ports[port] = sc.Name
+			container.Ports = append(container.Ports, v1.ContainerPort{ContainerPort: port})
+		}
+
+		env := make(map[string]string, len(tpl.Env)+len(sc.Env))
+		for k, v := range tpl.Env {
+			env[k] = v
+		}
+		for k, v := range sc.Env {
+			env[k] = v
+		}
+		keys := make([]string, 0, len(env))
+		for k := range env {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			container.Env = append(container.Env, v1.EnvVar{Name: k, Value: env[k]})
+		}
+
+		resources, err := sidecarResources(sc, tpl.CPU, tpl.Memory, tpl.MaxCPU, tpl.MaxMemory)
+		if err != nil {
+			return nil, invalid("%q: %v", sc.Name, err)
+		}
+		container.Resources = resources
+
+		if sc.Persistent {
+			if tpl.DataPath == "" {
+				return nil, invalid("%q can not be persistent", sc.CatalogId)
+			}
+			container.VolumeMounts = append(container.VolumeMounts, v1.VolumeMount{
+				Name:      workspaceVolume,
+				MountPath: tpl.DataPath,
+				SubPath:   sidecarsDir + "/" + sc.Name,
+			})
+		}
+		containers = append(containers, container)
+	}
+
+	return containers, nil
+}
+
+// sidecarResources 附加服务的资源,requests与limits相同,调用方指定的资源不能超过目录中的上限
+func sidecarResources(sc *pb.Sidecar, cpu, memory, maxCPU, maxMemory string) (v1.ResourceRequirements, error) {
+	if sc.ResourceLimit != nil {
+		if sc.ResourceLimit.Cpu != "" {
+			cpu = sc.ResourceLimit.Cpu
+		}
+		if sc.ResourceLimit.Memory != "" {
+			memory = sc.ResourceLimit.Memory
+		}
+	}
+
+	list := v1.ResourceList{}
+	for _, r := range []struct {
+		name       v1.ResourceName
+		value, max string
+	}{
+		{v1.ResourceCPU, cpu, maxCPU},
+		{v1.ResourceMemory, memory, maxMemory},
+	} {
+		if r.value == "" {
+			continue
+		}
+		q, err := resource.ParseQuantity(r.value)
+		if err != nil {
+			return v1.ResourceRequirements{}, fmt.Errorf("invalid %s %q", r.name, r.value)
+		}
+		if r.max != "" {
+			if max, err := resource.ParseQuantity(r.max); err == nil && q.Cmp(max) > 0 {
+				return v1.ResourceRequirements{}, fmt.Errorf("%s %s exceeds %s", r.name, r.value, r.max)
+			}
+		}
+		list[r.name] = q
+	}
+
+	return v1.ResourceRequirements{Requests: list, Limits: list.DeepCopy()}, nil
+}
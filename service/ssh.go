@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path"
+	"strings"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/pb"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/sshgateway"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/workspace"
+	"golang.org/x/crypto/ssh"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	sshContainer = "ssh-keys"
+	// maxSSHKeys 每个用户最多注册的公钥数量
+	maxSSHKeys = 32
+)
+
+// sshScript 将网关和所有者的公钥写入存储卷中的authorized_keys,覆盖已有内容,
+// 文件属于存储卷根目录的所有者,sshd要求目录和文件不能被其他用户写入
+const sshScript = `set -e
+OWNER=$(stat -c %u:%g "$SSH_VOLUME")
+FILE="$SSH_VOLUME/$SSH_AUTHORIZED_KEYS"
+DIR=$(dirname "$FILE")
+mkdir -p "$DIR"
+printf '%s\n' "$AUTHORIZED_KEYS" > "$FILE"
+chmod 700 "$DIR"
+chmod 600 "$FILE"
+chown "$OWNER" "$DIR" "$FILE"
+echo "authorized keys written to $FILE"`
+
+// GetSSHKeys 获取用户注册的SSH公钥
+func (s *CloudSpaceService) GetSSHKeys(ctx context.Context, query *pb.ProfileQuery) (*pb.SSHKeys, error) {
+	if err := validateOwner(query.Owner); err != nil {
+		return &pb.SSHKeys{}, err
+	}
+	// 直接读取API Server,更新后立即读取时缓存可能还没有同步
+	keys, err := workspace.SSHKeys(ctx, s.apiReader, query.Namespace, query.Owner)
+	if err != nil {
+		klog.Errorf("get ssh keys error:%v", err)
+		return &pb.SSHKeys{}, status.Error(codes.Unknown, ErrGetSSHKeys.Error())
+	}
+
+	return &pb.SSHKeys{Owner: query.Owner, Namespace: query.Namespace, Keys: keys}, nil
+}
+
+// SetSSHKeys 替换用户注册的所有SSH公钥,列表为空时删除。网关认证时实时读取,
+// 已经运行的工作空间中的authorized_keys在下次启动时更新
+func (s *CloudSpaceService) SetSSHKeys(ctx context.Context, req *pb.SSHKeys) (*pb.Response, error) {
+	if err := validateOwner(req.Owner); err != nil {
+		return ResponseFailed, err
+	}
+	if len(req.Keys) > maxSSHKeys {
+		return ResponseFailed, status.Errorf(codes.InvalidArgument, "%s: at most %d keys", ErrInvalidSSHKey.Error(), maxSSHKeys)
+	}
+	lines := make([]string, 0, len(req.Keys))
+	for i, key := range req.Keys {
+		// 只保存公钥和注释,不接受authorized_keys中的选项
+		pub, comment, options, _, err := ssh.ParseAuthorizedKey([]byte(key))
+		if err != nil || len(options) > 0 {
+			return ResponseFailed, status.Errorf(codes.InvalidArgument, "%s: key %d", ErrInvalidSSHKey.Error(), i)
+		}
+		line := sshgateway.AuthorizedKey(pub)
+		if comment != "" {
+			line += " " + comment
+		}
+		lines = append(lines, line)
+	}
+
+	key := client.ObjectKey{Name: workspace.SSHKeysSecretName(req.Owner), Namespace: req.Namespace}
+	secret := &v1.Secret{}
+	err := s.apiReader.Get(ctx, key, secret)
+	if err != nil && !errors.IsNotFound(err) {
+		klog.Errorf("get ssh keys error:%v", err)
+		return ResponseFailed, status.Error(codes.Unknown, ErrSetSSHKeys.Error())
+	}
+	exists := err == nil
+
+	if len(lines) == 0 {
+		if exists {
+			if err := s.client.Delete(ctx, secret); err != nil && !errors.IsNotFound(err) {
+				klog.Errorf("delete ssh keys error:%v", err)
+				return ResponseFailed, status.Error(codes.Unknown, ErrSetSSHKeys.Error())
+			}
+		}
+		return ResponseSuccess, nil
+	}
+
+	if !exists {
+		secret = &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+				Labels: map[string]string{
+					workspace.LabelKind:  workspace.KindSSHKeys,
+					workspace.LabelOwner: req.Owner,
+				},
+			},
+		}
+	}
+	secret.Data = map[string][]byte{workspace.SSHKeysDataKey: []byte(strings.Join(lines, "\n") + "\n")}
+	if exists {
+		err = s.client.Update(ctx, secret)
+	} else {
+		err = s.client.Create(ctx, secret)
+	}
+	if err != nil {
+		klog.Errorf("set ssh keys error:%v", err)
+		if errors.IsConflict(err) || errors.IsAlreadyExists(err) {
+			return ResponseFailed, status.Error(codes.Aborted, ErrSetSSHKeys.Error())
+		}
+		return ResponseFailed, status.Error(codes.Unknown, ErrSetSSHKeys.Error())
+	}
+	klog.Infof("[SetSSHKeys] updated ssh keys of %s, %d keys", req.Owner, len(lines))
+
+	return ResponseSuccess, nil
+}
+
+// applySSH 添加写入authorized_keys的init容器,网关的公钥总是写入,所有者的公钥用于在工作空间之间直接连接。
+// 读取密钥失败时不影响启动
+func (s *CloudSpaceService) applySSH(ctx context.Context, pod *v1.Pod, pvc *v1.PersistentVolumeClaim) {
+	owner := pvc.Labels[workspace.LabelOwner]
+	if !s.cfg.SSH.Enabled || owner == "" {
+		return
+	}
+	keys, err := sshgateway.LoadKeys(ctx, s.client, pvc.Namespace)
+	if err != nil {
+		klog.Warningf("load ssh gateway keys error:%v, skip ssh for %s", err, pvc.Name)
+		return
+	}
+	lines := []string{sshgateway.AuthorizedKey(keys.Client.PublicKey()) + " cloud-ide-ssh-gateway"}
+	ownerKeys, err := workspace.SSHKeys(ctx, s.client, pvc.Namespace, owner)
+	if err != nil {
+		klog.Warningf("get ssh keys error:%v, only gateway key is authorized for %s", err, pvc.Name)
+	}
+	lines = append(lines, ownerKeys...)
+
+	volumeRoot := strings.TrimSuffix(workspaceMountPath, "/")
+	pod.Spec.InitContainers = append(pod.Spec.InitContainers, v1.Container{
+		Name:            sshContainer,
+		Image:           s.cfg.SSH.Image,
+		ImagePullPolicy: v1.PullIfNotPresent,
+		Command:         []string{"sh", "-c", sshScript},
+		Env: []v1.EnvVar{
+			{Name: "SSH_VOLUME", Value: volumeRoot},
+			{Name: "SSH_AUTHORIZED_KEYS", Value: path.Clean(s.cfg.SSH.AuthorizedKeysPath)},
+			{Name: "AUTHORIZED_KEYS", Value: strings.Join(lines, "\n")},
+		},
+		VolumeMounts: []v1.VolumeMount{
+			{Name: workspaceVolume, MountPath: workspaceMountPath},
+		},
+	})
+}
+
+// sshCommand 返回连接工作空间的ssh命令,没有配置网关的公开地址或工作空间没有所有者时返回空
+func (s *CloudSpaceService) sshCommand(ctx context.Context, namespace, name string) string {
+	if !s.cfg.SSH.Enabled || s.cfg.SSH.PublicAddress == "" {
+		return ""
+	}
+	pvc := &v1.PersistentVolumeClaim{}
+	if err := s.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, pvc); err != nil {
+		return ""
+	}
+	if pvc.Labels[workspace.LabelOwner] == "" {
+		return ""
+	}
+
+	host, port, err := net.SplitHostPort(s.cfg.SSH.PublicAddress)
+	if err != nil {
+		return fmt.Sprintf("ssh %s@%s", name, s.cfg.SSH.PublicAddress)
+	}
+	return fmt.Sprintf("ssh -p %s %s@%s", port, name, host)
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/pb"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/events"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/workspace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+/*
+	回收站: 配置了保留时间时DeleteSpace不再直接删除PVC,而是停止Pod、删除预览,并为PVC添加trashed标签和计划删除时间,
+	保留期内可以通过RestoreDeletedSpace恢复,保留期过后由TrashPurger彻底删除。回收站中的工作空间名称不能用于创建新的工作空间
+*/
+
+// trashSpace 停止工作空间并将其移入回收站,可以重复调用
+func (s *CloudSpaceService) trashSpace(ctx context.Context, pvc *v1.PersistentVolumeClaim) (*pb.Response, error) {
+	if pvc.Labels[workspace.LabelState] != workspace.StateTrashed {
+		now := time.Now()
+		purgeAt := now.Add(s.cfg.Trash.Retention.Duration)
+		patch := []byte(fmt.Sprintf(`{"metadata":{"labels":{%q:%q},"annotations":{%q:%q,%q:%q}}}`,
+			workspace.LabelState, workspace.StateTrashed,
+			workspace.AnnotationTrashedAt, now.UTC().Format(time.RFC3339),
+			workspace.AnnotationPurgeAt, purgeAt.UTC().Format(time.RFC3339)))
+		if err := s.client.Patch(ctx, pvc, client.RawPatch(types.MergePatchType, patch)); err != nil {
+			klog.Errorf("trash pvc error:%v", err)
+			return ResponseFailed, status.Error(codes.Unknown, ErrDeletePVC.Error())
+		}
+		klog.Infof("[DeleteSpace] workspace moved to trash, purge at %s", purgeAt.Format(time.RFC3339))
+		s.recorder.Eventf(pvc, v1.EventTypeNormal, events.ReasonWorkspaceTrashed, "workspace moved to trash, purge at %s", purgeAt.Format(time.RFC3339))
+	}
+
+	// 删除预览,回收站中的工作空间不再对外暴露端口
+	if err := s.removePreviews(ctx, pvc); err != nil {
+		klog.Errorf("remove previews error:%v", err)
+		return ResponseFailed, status.Error(codes.Unknown, ErrUnexposePort.Error())
+	}
+
+	// 停止工作空间
+	pod, err := s.backend.Get(ctx, pvc.Namespace, pvc.Name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ResponseSuccess, nil
+		}
+		klog.Errorf("get pod error:%v", err)
+		return ResponseFailed, status.Error(codes.Unknown, ErrDeletePod.Error())
+	}
+	if pod.DeletionTimestamp != nil {
+		return ResponseSuccess, nil
+	}
+
+	return s.deletePod(pod, workspace.StopReasonTrashed)
+}
+
+// RestoreDeletedSpace 从回收站中恢复工作空间,恢复后工作空间处于停止状态
+func (s *CloudSpaceService) RestoreDeletedSpace(ctx context.Context, option *pb.QueryOption) (*pb.Response, error) {
+	pvc := &v1.PersistentVolumeClaim{}
+	err := s.client.Get(ctx, client.ObjectKey{Name: option.Name, Namespace: option.Namespace}, pvc)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ResponseFailed, status.Error(codes.NotFound, ErrWorkspaceNotFound.Error())
+		}
+		klog.Errorf("get pvc error:%v", err)
+		return ResponseFailed, status.Error(codes.Unknown, ErrRestoreSpace.Error())
+	}
+	// 已经开始彻底删除,无法恢复
+	if pvc.DeletionTimestamp != nil {
+		return ResponseFailed, status.Error(codes.FailedPrecondition, ErrWorkspaceDeleting.Error())
+	}
+	if pvc.Labels[workspace.LabelState] != workspace.StateTrashed {
+		return ResponseSuccess, nil
+	}
+
+	patch := []byte(fmt.Sprintf(`{"metadata":{"labels":{%q:null},"annotations":{%q:null,%q:null}}}`,
+		workspace.LabelState, workspace.AnnotationTrashedAt, workspace.AnnotationPurgeAt))
+	if err := s.client.Patch(ctx, pvc, client.RawPatch(types.MergePatchType, patch)); err != nil {
+		klog.Errorf("restore pvc error:%v", err)
+		return ResponseFailed, status.Error(codes.Unknown, ErrRestoreSpace.Error())
+	}
+	klog.Info("[RestoreDeletedSpace] workspace restored")
+	s.recorder.Event(pvc, v1.EventTypeNormal, events.ReasonWorkspaceRestored, "workspace restored from trash")
+
+	return ResponseSuccess, nil
+}
+
+// ListTrashedSpaces 列出回收站中的工作空间
+func (s *CloudSpaceService) ListTrashedSpaces(ctx context.Context, option *pb.ListOption) (*pb.TrashedSpaceList, error) {
+	pvcs := &v1.PersistentVolumeClaimList{}
+	err := s.client.List(ctx, pvcs, client.InNamespace(option.Namespace), client.MatchingLabels{workspace.LabelState: workspace.StateTrashed})
+	if err != nil {
+		klog.Errorf("list trashed pvc error:%v", err)
+		return &pb.TrashedSpaceList{}, status.Error(codes.Unknown, ErrListTrashedSpaces.Error())
+	}
+
+	list := &pb.TrashedSpaceList{}
+	for i := range pvcs.Items {
+		pvc := &pvcs.Items[i]
+		trashedAt, _ := time.Parse(time.RFC3339, pvc.Annotations[workspace.AnnotationTrashedAt])
+		purgeAt, _ := time.Parse(time.RFC3339, pvc.Annotations[workspace.AnnotationPurgeAt])
+		list.Spaces = append(list.Spaces, &pb.TrashedSpace{
+			Name:      pvc.Name,
+			Namespace: pvc.Namespace,
+			TrashedAt: trashedAt.Unix(),
+			PurgeAt:   purgeAt.Unix(),
+		})
+	}
+
+	return list, nil
+}
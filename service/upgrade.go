@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/pb"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/events"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/workspace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// 工作空间的升级动作
+const (
+	UpgradeUpToDate       = "UpToDate"
+	UpgradeScheduled      = "Scheduled"
+	UpgradePendingRestart = "PendingRestart"
+	UpgradeSkipped        = "Skipped"
+)
+
+// UpgradeSpaces 将匹配的工作空间升级到镜像目录中的新版本,升级后的镜像记录在PVC上,
+// 停止的工作空间下次启动时使用新镜像,运行中的工作空间在下次停止后或维护窗口内由Upgrader重启
+func (s *CloudSpaceService) UpgradeSpaces(ctx context.Context, req *pb.UpgradeRequest) (*pb.UpgradeResult, error) {
+	if s.catalog == nil {
+		return &pb.UpgradeResult{}, status.Error(codes.FailedPrecondition, ErrCatalogDisabled.Error())
+	}
+	img, ok, err := s.catalog.Get(ctx, req.CatalogId)
+	if err != nil {
+		klog.Errorf("get catalog image error:%v", err)
+		return &pb.UpgradeResult{}, status.Error(codes.Unknown, ErrListImages.Error())
+	}
+	if !ok {
+		return &pb.UpgradeResult{}, status.Error(codes.InvalidArgument, ErrImageNotFound.Error())
+	}
+	target := s.catalog.Pin(ctx, img)
+
+	selector := client.MatchingLabels{}
+	for k, v := range req.Selector {
+		selector[k] = v
+	}
+	selector[workspace.LabelKind] = workspace.KindCloudIde
+	pvcs := &v1.PersistentVolumeClaimList{}
+	if err := s.client.List(ctx, pvcs, client.InNamespace(req.Namespace), selector); err != nil {
+		klog.Errorf("list pvc error:%v", err)
+		return &pb.UpgradeResult{}, status.Error(codes.Unknown, ErrUpgradeSpaces.Error())
+	}
+	names := make(map[string]bool, len(req.Names))
+	for _, name := range req.Names {
+		names[name] = true
+	}
+
+	res := &pb.UpgradeResult{DryRun: req.DryRun}
+	for i := range pvcs.Items {
+		pvc := &pvcs.Items[i]
+		if len(names) > 0 && !names[pvc.Name] {
+			continue
+		}
+		res.Spaces = append(res.Spaces, s.upgradeSpace(ctx, pvc, target, req.DryRun))
+	}
+	klog.Infof("[UpgradeSpaces] %d workspaces matched, image:%s, dryRun:%v", len(res.Spaces), target, req.DryRun)
+
+	return res, nil
+}
+
+func (s *CloudSpaceService) upgradeSpace(ctx context.Context, pvc *v1.PersistentVolumeClaim, target string, dryRun bool) *pb.SpaceUpgrade {
+	res := &pb.SpaceUpgrade{
+		Name:      pvc.Name,
+		Namespace: pvc.Namespace,
+		FromImage: pvc.Annotations[workspace.AnnotationImage],
+		ToImage:   target,
+	}
+	if pvc.DeletionTimestamp != nil || pvc.Labels[workspace.LabelState] == workspace.StateTrashed {
+		res.Action, res.Message = UpgradeSkipped, "workspace is deleted"
+		return res
+	}
+
+	// 运行中的工作空间以Pod的镜像为准
+	running := false
+	pod, err := s.backend.Get(ctx, pvc.Namespace, pvc.Name)
+	if err != nil && !errors.IsNotFound(err) {
+		klog.Errorf("get pod error:%v", err)
+		res.Action, res.Message = UpgradeSkipped, ErrUpgradeSpaces.Error()
+		return res
+	}
+	if err == nil && pod.DeletionTimestamp == nil {
+		if c := workspace.Container(pod); c != nil {
+			running = true
+			res.FromImage = c.Image
+		}
+	}
+
+	switch {
+	case res.FromImage == target && pvc.Annotations[workspace.AnnotationImage] == target:
+		res.Action = UpgradeUpToDate
+		return res
+	case running && res.FromImage != target:
+		res.Action, res.Message = UpgradePendingRestart, "restart at next stop or in maintenance window"
+	default:
+		res.Action, res.Message = UpgradeScheduled, "upgrade at next start"
+	}
+	if dryRun || pvc.Annotations[workspace.AnnotationImage] == target {
+		return res
+	}
+
+	patch := []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, workspace.AnnotationImage, target))
+	if err := s.client.Patch(ctx, pvc, client.RawPatch(types.MergePatchType, patch)); err != nil {
+		klog.Errorf("patch pvc error:%v", err)
+		res.Action, res.Message = UpgradeSkipped, ErrUpgradeSpaces.Error()
+		return res
+	}
+	s.recorder.Eventf(pvc, v1.EventTypeNormal, events.ReasonUpgradeScheduled, "upgrade to image %s scheduled", target)
+
+	return res
+}
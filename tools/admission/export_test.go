@@ -0,0 +1,21 @@
+package admission
+
+import (
+	"context"
+	"time"
+)
+
+// Process 处理一次所有层级的队首
+func (q *Queue) Process(ctx context.Context) {
+	q.process(ctx)
+}
+
+// SetQueuedAt 修改入队时间,模拟排队超时
+func (e *Entry) SetQueuedAt(t time.Time) {
+	e.queuedAt = t
+}
+
+// Err 出队时的错误
+func (e *Entry) Err() error {
+	return e.err
+}
@@ -0,0 +1,413 @@
+package admission
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/conf"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/backend"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/capacity"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/workspace"
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+/*
+	准入队列: 层级中没有排队的请求并且资源足够(capacity.Estimator)时直接启动,否则进入队列。
+	每个层级(调度配置)一个FIFO队列,只有队首的请求会创建Pod,
+	队首的Pod无法调度时保持Pending,由kube-scheduler在资源释放后调度(集群自动扩容也依赖Pending的Pod),
+	Pod调度成功后队首出队,进入正常的启动流程,后面的请求依次前移。
+	调用方超时后请求仍然保留在队列中,资源释放后自动启动,超过MaxWait或被取消时出队并删除Pod。
+	队列只保存在处理请求的控制器进程内存中,重启后队列中的请求丢失,已经创建的队首Pod由PodReconciler按截止时间处理
+*/
+
+var (
+	ErrQueueTimeout  = errors.New("wait for resources timeout")
+	ErrQueueCanceled = errors.New("queued request canceled")
+)
+
+// finishedRetention 出队的请求保留的时间
+const finishedRetention = time.Minute * 5
+
+// 请求在队列中的状态
+const (
+	StateQueued   = "Queued"
+	StateAdmitted = "Admitted"
+	StateTimeout  = "Timeout"
+	StateCanceled = "Canceled"
+	StateFailed   = "Failed"
+)
+
+var (
+	queueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "cloud_ide_admission_queue_depth",
+		Help: "Number of workspace start requests waiting for resources, by tier.",
+	}, []string{"tier"})
+	waitSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "cloud_ide_admission_wait_seconds",
+		Help:    "Time workspace start requests spent in the admission queue, by tier and result.",
+		Buckets: []float64{1, 5, 15, 30, 60, 120, 300, 600, 1800, 3600},
+	}, []string{"tier", "result"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(queueDepth, waitSeconds)
+}
+
+// Position 请求在队列中的位置,从1开始,出队后为0
+type Position struct {
+	Tier     string
+	Position int
+	Depth    int
+	QueuedAt time.Time
+	State    string
+}
+
+// Entry 队列中的一个请求
+type Entry struct {
+	key      client.ObjectKey
+	tier     string
+	pod      *v1.Pod
+	queuedAt time.Time
+	created  bool
+	// 创建Pod的时间,刚创建的Pod可能还没有同步到缓存中
+	createdAt time.Time
+	state     string
+	err       error
+	done      chan struct{}
+
+	finishedAt time.Time
+}
+
+// Done 请求出队时关闭
+func (e *Entry) Done() <-chan struct{} {
+	return e.done
+}
+
+// Queue 准入队列,实现了manager.Runnable,在每个副本上运行,处理本副本收到的请求,未启用时为nil
+type Queue struct {
+	client       client.Client
+	estimator    *capacity.Estimator
+	backend      backend.WorkspaceBackend
+	cfg          conf.AdmissionConfig
+	startTimeout time.Duration
+
+	mu      sync.Mutex
+	tiers   map[string][]*Entry
+	entries map[client.ObjectKey]*Entry
+	kick    chan struct{}
+}
+
+func NewQueue(c client.Client, estimator *capacity.Estimator, backend backend.WorkspaceBackend, cfg conf.AdmissionConfig, startTimeout time.Duration) *Queue {
+	return &Queue{
+		client:       c,
+		estimator:    estimator,
+		backend:      backend,
+		cfg:          cfg,
+		startTimeout: startTimeout,
+		tiers:        make(map[string][]*Entry),
+		entries:      make(map[client.ObjectKey]*Entry),
+		kick:         make(chan struct{}, 1),
+	}
+}
+
+// NeedLeaderElection 队列保存在处理请求的副本中,每个副本都需要运行
+func (q *Queue) NeedLeaderElection() bool {
+	return false
+}
+
+func (q *Queue) Start(ctx context.Context) error {
+	ticker := time.NewTicker(q.cfg.PollInterval.Duration)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case <-q.kick:
+		}
+		q.process(ctx)
+	}
+}
+
+// Submit 将Pod加入层级tier的队列,同一个工作空间已经在队列中时返回已有的请求
+func (q *Queue) Submit(tier string, pod *v1.Pod) *Entry {
+	e, _ := q.submit(tier, pod)
+	return e
+}
+
+// submit 返回的请求不是由本次调用加入时owned为false,请求中的Pod属于其它调用方
+func (q *Queue) submit(tier string, pod *v1.Pod) (*Entry, bool) {
+	key := client.ObjectKeyFromObject(pod)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if e, ok := q.entries[key]; ok && e.state == StateQueued {
+		return e, false
+	}
+
+	e := &Entry{
+		key:      key,
+		tier:     tier,
+		pod:      pod,
+		queuedAt: time.Now(),
+		state:    StateQueued,
+		done:     make(chan struct{}),
+	}
+	q.entries[key] = e
+	q.tiers[tier] = append(q.tiers[tier], e)
+	queueDepth.WithLabelValues(tier).Set(float64(len(q.tiers[tier])))
+	q.trigger()
+
+	return e, true
+}
+
+// Wait 资源足够时直接创建Pod,否则将Pod加入队列并等待其被调度,返回nil时Pod已经创建,
+// ctx结束时请求仍然保留在队列中,返回ctx的错误。
+// 同一个工作空间已经由其它调用方排队时等待该请求的结果,出队成功后返回AlreadyExists,调用方按已有的Pod处理
+func (q *Queue) Wait(ctx context.Context, tier string, pod *v1.Pod) error {
+	if q.fits(ctx, tier, pod) {
+		return q.backend.Start(ctx, pod)
+	}
+	e, owned := q.submit(tier, pod)
+	select {
+	case <-e.done:
+		if !owned && e.err == nil {
+			return apierrors.NewAlreadyExists(v1.Resource("pods"), pod.Name)
+		}
+		return e.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// fits 层级中没有排队的请求并且当前资源足够时可以直接启动,估算失败时排队
+func (q *Queue) fits(ctx context.Context, tier string, pod *v1.Pod) bool {
+	q.mu.Lock()
+	e, ok := q.entries[client.ObjectKeyFromObject(pod)]
+	waiting := len(q.tiers[tier]) > 0 || (ok && e.state == StateQueued)
+	q.mu.Unlock()
+	if waiting {
+		return false
+	}
+
+	report, err := q.estimator.Estimate(ctx, pod)
+	if err != nil {
+		klog.Errorf("estimate capacity error:%v, queue workspace %s", err, pod.Name)
+		return false
+	}
+
+	return report.Fits()
+}
+
+// Position 返回工作空间的排队位置,没有排队时返回false
+func (q *Queue) Position(key client.ObjectKey) (Position, bool) {
+	if q == nil {
+		return Position{}, false
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	e, ok := q.entries[key]
+	if !ok {
+		return Position{}, false
+	}
+	pos := Position{Tier: e.tier, QueuedAt: e.queuedAt, State: e.state, Depth: len(q.tiers[e.tier])}
+	for i, entry := range q.tiers[e.tier] {
+		if entry == e {
+			pos.Position = i + 1
+			break
+		}
+	}
+
+	return pos, true
+}
+
+// Depth 返回层级中排队的请求数
+func (q *Queue) Depth(tier string) int {
+	if q == nil {
+		return 0
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.tiers[tier])
+}
+
+// Cancel 取消排队中的请求,已经创建的Pod会被删除,没有排队时返回false
+func (q *Queue) Cancel(ctx context.Context, key client.ObjectKey) bool {
+	if q == nil {
+		return false
+	}
+	q.mu.Lock()
+	e, ok := q.entries[key]
+	if !ok || e.state != StateQueued {
+		q.mu.Unlock()
+		return false
+	}
+	created := e.created
+	q.finish(e, StateCanceled, ErrQueueCanceled)
+	q.mu.Unlock()
+
+	if created {
+		q.deletePod(ctx, e.pod, workspace.StopReasonQueueCanceled)
+	}
+
+	return true
+}
+
+// process 依次处理每个层级的队首,队首被调度后继续处理下一个
+func (q *Queue) process(ctx context.Context) {
+	q.mu.Lock()
+	tiers := make([]string, 0, len(q.tiers))
+	for tier := range q.tiers {
+		tiers = append(tiers, tier)
+	}
+	q.mu.Unlock()
+
+	for _, tier := range tiers {
+		for q.processHead(ctx, tier) {
+		}
+	}
+	q.prune()
+}
+
+// processHead 处理队首的请求,队首出队时返回true
+func (q *Queue) processHead(ctx context.Context, tier string) bool {
+	q.mu.Lock()
+	if len(q.tiers[tier]) == 0 {
+		delete(q.tiers, tier)
+		q.mu.Unlock()
+		return false
+	}
+	e := q.tiers[tier][0]
+	created := e.created
+	q.mu.Unlock()
+
+	if time.Since(e.queuedAt) > q.cfg.MaxWait.Duration {
+		q.mu.Lock()
+		q.finish(e, StateTimeout, ErrQueueTimeout)
+		q.mu.Unlock()
+		if created {
+			q.deletePod(ctx, e.pod, workspace.StopReasonQueueTimeout)
+		}
+		klog.Infof("queued workspace timeout, workspace:%s", e.key)
+		return true
+	}
+
+	if !created {
+		// 队首的Pod在排队结束之前不会被PodReconciler删除
+		workspace.SetStarting(e.pod, e.queuedAt.Add(q.cfg.MaxWait.Duration+q.startTimeout))
+		err := q.backend.Start(ctx, e.pod)
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		if e.state != StateQueued {
+			// 创建Pod期间请求已经被取消
+			if err == nil {
+				go q.deletePod(context.Background(), e.pod, workspace.StopReasonQueueCanceled)
+			}
+			return true
+		}
+		if err != nil {
+			// 已经存在的Pod交给调用方按原来的流程处理
+			q.finish(e, StateFailed, err)
+			return true
+		}
+		e.created = true
+		e.createdAt = time.Now()
+		return false
+	}
+
+	pod, err := q.backend.Get(ctx, e.key.Namespace, e.key.Name)
+	if err != nil {
+		if apierrors.IsNotFound(err) && time.Since(e.createdAt) > time.Second*30 {
+			// Pod被其它流程删除
+			q.mu.Lock()
+			q.finish(e, StateCanceled, ErrQueueCanceled)
+			q.mu.Unlock()
+			return true
+		}
+		if !apierrors.IsNotFound(err) {
+			klog.Errorf("get queued pod error:%v", err)
+		}
+		return false
+	}
+	if !scheduled(pod) {
+		return false
+	}
+
+	// 调度成功,从现在开始计算启动的截止时间
+	workspace.SetStarting(pod, time.Now().Add(q.startTimeout))
+	if err := q.client.Update(ctx, pod); err != nil {
+		klog.Errorf("update queued pod deadline error:%v", err)
+		return false
+	}
+	q.mu.Lock()
+	q.finish(e, StateAdmitted, nil)
+	q.mu.Unlock()
+	klog.Infof("queued workspace admitted, workspace:%s, waited:%v", e.key, time.Since(e.queuedAt))
+
+	return true
+}
+
+// finish 请求出队,调用时需要持有锁
+func (q *Queue) finish(e *Entry, state string, err error) {
+	if e.state != StateQueued {
+		return
+	}
+	e.state = state
+	e.err = err
+	e.finishedAt = time.Now()
+	close(e.done)
+
+	entries := q.tiers[e.tier]
+	for i, entry := range entries {
+		if entry == e {
+			q.tiers[e.tier] = append(entries[:i:i], entries[i+1:]...)
+			break
+		}
+	}
+	queueDepth.WithLabelValues(e.tier).Set(float64(len(q.tiers[e.tier])))
+	waitSeconds.WithLabelValues(e.tier, state).Observe(time.Since(e.queuedAt).Seconds())
+}
+
+// prune 出队的请求保留一段时间用于查询最终状态,之后删除
+func (q *Queue) prune() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for key, e := range q.entries {
+		if e.state != StateQueued && time.Since(e.finishedAt) > finishedRetention {
+			delete(q.entries, key)
+		}
+	}
+}
+
+func (q *Queue) deletePod(ctx context.Context, pod *v1.Pod, reason string) {
+	if err := q.backend.Stop(ctx, pod.Namespace, pod.Name, reason); err != nil && !apierrors.IsNotFound(err) {
+		klog.Errorf("delete queued pod error:%v, pod:%s", err, pod.Name)
+	}
+}
+
+func (q *Queue) trigger() {
+	select {
+	case q.kick <- struct{}{}:
+	default:
+	}
+}
+
+func scheduled(pod *v1.Pod) bool {
+	if pod.Spec.NodeName != "" {
+		return true
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == v1.PodScheduled {
+			return cond.Status == v1.ConditionTrue
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,146 @@
+package admission_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/conf"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/admission"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/backend"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/capacity"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const namespace = "cloud-ide"
+
+// hookBackend 在创建Pod之前调用beforeStart,用于模拟与创建并发的操作
+type hookBackend struct {
+	backend.WorkspaceBackend
+	beforeStart func(pod *v1.Pod)
+}
+
+func (b *hookBackend) Start(ctx context.Context, pod *v1.Pod) error {
+	if b.beforeStart != nil {
+		b.beforeStart(pod)
+	}
+
+	return b.WorkspaceBackend.Start(ctx, pod)
+}
+
+func newPod(name string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       v1.PodSpec{Containers: []v1.Container{{Name: name, Image: "code-server:v1"}}},
+	}
+}
+
+var _ = Describe("Queue", func() {
+	var (
+		ctx context.Context
+		c   client.Client
+		b   *hookBackend
+		q   *admission.Queue
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		// 集群中没有节点,所有请求都需要排队
+		c = fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+		b = &hookBackend{WorkspaceBackend: backend.NewPodBackend(c)}
+		q = admission.NewQueue(c, capacity.NewEstimator(c, c), b, conf.AdmissionConfig{
+			Enabled:      true,
+			MaxWait:      metav1.Duration{Duration: time.Hour},
+			PollInterval: metav1.Duration{Duration: time.Second},
+		}, time.Minute)
+	})
+
+	exists := func(name string) bool {
+		err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &v1.Pod{})
+		Expect(client.IgnoreNotFound(err)).To(Succeed())
+		return err == nil
+	}
+
+	// schedule 模拟kube-scheduler调度Pod
+	schedule := func(name string) {
+		pod := &v1.Pod{}
+		Expect(c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, pod)).To(Succeed())
+		pod.Spec.NodeName = "node-1"
+		Expect(c.Update(ctx, pod)).To(Succeed())
+	}
+
+	It("creates only the head of each tier", func() {
+		q.Submit("small", newPod("ws-1"))
+		q.Submit("small", newPod("ws-2"))
+		q.Submit("large", newPod("ws-3"))
+		q.Process(ctx)
+
+		Expect(exists("ws-1")).To(BeTrue())
+		Expect(exists("ws-2")).To(BeFalse())
+		Expect(exists("ws-3")).To(BeTrue())
+		pos, ok := q.Position(client.ObjectKey{Namespace: namespace, Name: "ws-2"})
+		Expect(ok).To(BeTrue())
+		Expect(pos.Position).To(Equal(2))
+
+		// 队首调度后出队,下一个请求成为队首
+		schedule("ws-1")
+		q.Process(ctx)
+		Expect(exists("ws-2")).To(BeTrue())
+		Expect(q.Depth("small")).To(Equal(1))
+		pos, _ = q.Position(client.ObjectKey{Namespace: namespace, Name: "ws-1"})
+		Expect(pos.State).To(Equal(admission.StateAdmitted))
+	})
+
+	It("deletes the created pod when the head waits longer than MaxWait", func() {
+		e := q.Submit("small", newPod("ws-1"))
+		q.Process(ctx)
+		Expect(exists("ws-1")).To(BeTrue())
+
+		e.SetQueuedAt(time.Now().Add(-time.Hour * 2))
+		q.Process(ctx)
+		Expect(e.Done()).To(BeClosed())
+		Expect(e.Err()).To(MatchError(admission.ErrQueueTimeout))
+		Expect(exists("ws-1")).To(BeFalse())
+		Expect(q.Depth("small")).To(BeZero())
+	})
+
+	It("deletes the pod when the request is canceled while the pod is being created", func() {
+		key := client.ObjectKey{Namespace: namespace, Name: "ws-1"}
+		canceled := false
+		b.beforeStart = func(pod *v1.Pod) {
+			canceled = q.Cancel(ctx, key)
+		}
+		e := q.Submit("small", newPod("ws-1"))
+		q.Process(ctx)
+
+		Expect(canceled).To(BeTrue())
+		Expect(e.Done()).To(BeClosed())
+		Expect(e.Err()).To(MatchError(admission.ErrQueueCanceled))
+		Eventually(func() bool { return exists("ws-1") }).Should(BeFalse())
+	})
+
+	It("returns AlreadyExists to a second caller waiting for the same workspace", func() {
+		first, second := make(chan error, 1), make(chan error, 1)
+		go func() { first <- q.Wait(ctx, "small", newPod("ws-1")) }()
+		Eventually(func() int { return q.Depth("small") }).Should(Equal(1))
+		go func() { second <- q.Wait(ctx, "small", newPod("ws-1")) }()
+		// 第二个调用方等待同一个请求,不会再次入队
+		Consistently(second, time.Millisecond*200).ShouldNot(Receive())
+		Expect(q.Depth("small")).To(Equal(1))
+
+		q.Process(ctx)
+		schedule("ws-1")
+		q.Process(ctx)
+
+		Eventually(first).Should(Receive(BeNil()))
+		var err error
+		Eventually(second).Should(Receive(&err))
+		Expect(apierrors.IsAlreadyExists(err)).To(BeTrue())
+	})
+})
@@ -0,0 +1,77 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/workspace"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+/*
+	工作空间后端: 决定工作空间的Pod由谁创建和管理。
+	pod: 直接创建Pod(默认),节点排空或驱逐后Pod被删除,工作空间停止;
+	statefulset: 每个工作空间一个单副本StatefulSet,启动时副本数为1,停止时缩为0,
+	被驱逐的Pod由StatefulSet重新创建。
+	两种后端都能识别另一种后端创建的Pod,切换配置后运行中的工作空间可以正常停止
+*/
+
+const (
+	KindPod         = "pod"
+	KindStatefulSet = "statefulset"
+)
+
+// WorkspaceBackend 工作空间后端,工作空间通过名称标识,与PVC的名称相同
+type WorkspaceBackend interface {
+	// Start 以pod为模板启动工作空间,pod的名称为工作空间名称,工作空间已经有Pod时返回AlreadyExists错误
+	Start(ctx context.Context, pod *v1.Pod) error
+	// Stop 停止工作空间,reason记录在Pod的注解中,工作空间没有运行时返回NotFound错误
+	Stop(ctx context.Context, namespace, name, reason string) error
+	// Get 返回工作空间当前的Pod,没有Pod时返回NotFound错误
+	Get(ctx context.Context, namespace, name string) (*v1.Pod, error)
+}
+
+// New 根据配置创建后端,kind为空时使用pod
+func New(kind string, c client.Client) (WorkspaceBackend, error) {
+	switch kind {
+	case KindPod, "":
+		return NewPodBackend(c), nil
+	case KindStatefulSet:
+		return NewStatefulSetBackend(c), nil
+	default:
+		return nil, fmt.Errorf("unknown workspace backend %q", kind)
+	}
+}
+
+// getPod 依次按名称查找Pod,都不存在时返回NotFound错误
+func getPod(ctx context.Context, c client.Client, namespace string, names ...string) (*v1.Pod, error) {
+	for _, name := range names {
+		pod := &v1.Pod{}
+		err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, pod)
+		if err == nil {
+			return pod, nil
+		}
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+	}
+
+	return nil, apierrors.NewNotFound(v1.Resource("pods"), names[0])
+}
+
+// setStopped 记录工作空间期望停止,Pod已经消失、等待自动恢复的工作空间也不再被重新创建
+func setStopped(ctx context.Context, c client.Client, namespace, name string) error {
+	return client.IgnoreNotFound(workspace.SetDesiredStopped(ctx, c, namespace, name))
+}
+
+// setRunning 启动成功后将PVC记录为期望运行,与Stop对应
+func setRunning(ctx context.Context, c client.Client, namespace, name string) error {
+	return client.IgnoreNotFound(workspace.SetDesiredRunning(ctx, c, namespace, name))
+}
+
+// podName StatefulSet创建的Pod名称
+func podName(name string) string {
+	return name + "-0"
+}
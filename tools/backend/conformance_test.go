@@ -0,0 +1,209 @@
+package backend_test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/backend"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/workspace"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	namespace = "cloud-ide"
+	name      = "ws-1"
+)
+
+// trackingClient 为创建的对象分配UID(fake client不会分配),并记录Pod被删除时的停止原因
+type trackingClient struct {
+	client.Client
+	uid         int
+	stopReasons map[string]string
+}
+
+func newTrackingClient() *trackingClient {
+	return &trackingClient{
+		Client:      fake.NewClientBuilder().WithScheme(scheme.Scheme).Build(),
+		stopReasons: make(map[string]string),
+	}
+}
+
+func (c *trackingClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if obj.GetUID() == "" {
+		c.uid++
+		obj.SetUID(types.UID(fmt.Sprintf("uid-%d", c.uid)))
+	}
+
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *trackingClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	if _, ok := obj.(*v1.Pod); ok {
+		current := &v1.Pod{}
+		if err := c.Client.Get(ctx, client.ObjectKeyFromObject(obj), current); err == nil {
+			c.stopReasons[current.Name] = current.Annotations[workspace.AnnotationStopReason]
+		}
+	}
+
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+// settleFunc 模拟集群中的控制器,使后端创建的对象达到稳定状态
+type settleFunc func(ctx context.Context, c *trackingClient)
+
+// syncStatefulSets 模拟StatefulSet控制器: 副本数为1时按模板创建<名称>-0,为0时删除
+func syncStatefulSets(ctx context.Context, c *trackingClient) {
+	sets := &appsv1.StatefulSetList{}
+	Expect(c.List(ctx, sets)).To(Succeed())
+	for i := range sets.Items {
+		sts := &sets.Items[i]
+		pod := &v1.Pod{}
+		err := c.Get(ctx, client.ObjectKey{Namespace: sts.Namespace, Name: sts.Name + "-0"}, pod)
+		Expect(client.IgnoreNotFound(err)).To(Succeed())
+		running := err == nil
+
+		switch {
+		case *sts.Spec.Replicas > 0 && !running:
+			controller := true
+			pod = &v1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        sts.Name + "-0",
+					Namespace:   sts.Namespace,
+					Labels:      sts.Spec.Template.Labels,
+					Annotations: sts.Spec.Template.Annotations,
+					OwnerReferences: []metav1.OwnerReference{{
+						APIVersion: "apps/v1",
+						Kind:       "StatefulSet",
+						Name:       sts.Name,
+						UID:        sts.UID,
+						Controller: &controller,
+					}},
+				},
+				Spec: sts.Spec.Template.Spec,
+			}
+			Expect(c.Create(ctx, pod)).To(Succeed())
+		case *sts.Spec.Replicas == 0 && running:
+			Expect(c.Delete(ctx, pod)).To(Succeed())
+		}
+	}
+}
+
+func template(image string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				workspace.LabelKind:      workspace.KindCloudIde,
+				workspace.LabelWorkspace: name,
+			},
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{{Name: name, Image: image}},
+			Volumes: []v1.Volume{{
+				Name: "volume-user-workspace",
+				VolumeSource: v1.VolumeSource{
+					PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: name},
+				},
+			}},
+		},
+	}
+}
+
+// conformance 所有后端都需要满足的行为
+func conformance(newBackend func(c client.Client) backend.WorkspaceBackend, settle settleFunc) {
+	var (
+		ctx context.Context
+		c   *trackingClient
+		b   backend.WorkspaceBackend
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		c = newTrackingClient()
+		b = newBackend(c)
+	})
+
+	start := func(image string) {
+		Expect(b.Start(ctx, template(image))).To(Succeed())
+		settle(ctx, c)
+	}
+
+	It("returns NotFound for a workspace that is not running", func() {
+		_, err := b.Get(ctx, namespace, name)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("starts the workspace from the pod template", func() {
+		start("code-server:v1")
+
+		pod, err := b.Get(ctx, namespace, name)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(workspace.Name(pod)).To(Equal(name))
+		Expect(workspace.ClaimName(pod)).To(Equal(name))
+		Expect(workspace.Container(pod).Image).To(Equal("code-server:v1"))
+	})
+
+	It("rejects starting a workspace that is already running", func() {
+		start("code-server:v1")
+
+		err := b.Start(ctx, template("code-server:v1"))
+		Expect(apierrors.IsAlreadyExists(err)).To(BeTrue())
+	})
+
+	It("stops the workspace and records the stop reason", func() {
+		start("code-server:v1")
+		pod, err := b.Get(ctx, namespace, name)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(b.Stop(ctx, namespace, name, workspace.StopReasonRequested)).To(Succeed())
+		settle(ctx, c)
+
+		_, err = b.Get(ctx, namespace, name)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		Expect(c.stopReasons).To(HaveKeyWithValue(pod.Name, workspace.StopReasonRequested))
+	})
+
+	It("returns NotFound when stopping a stopped workspace", func() {
+		err := b.Stop(ctx, namespace, name, workspace.StopReasonRequested)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+
+		start("code-server:v1")
+		Expect(b.Stop(ctx, namespace, name, workspace.StopReasonRequested)).To(Succeed())
+		settle(ctx, c)
+		err = b.Stop(ctx, namespace, name, workspace.StopReasonRequested)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("uses the new template when restarted", func() {
+		start("code-server:v1")
+		Expect(b.Stop(ctx, namespace, name, workspace.StopReasonUpgrade)).To(Succeed())
+		settle(ctx, c)
+
+		start("code-server:v2")
+		pod, err := b.Get(ctx, namespace, name)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(workspace.Container(pod).Image).To(Equal("code-server:v2"))
+	})
+}
+
+var _ = Describe("PodBackend", func() {
+	conformance(func(c client.Client) backend.WorkspaceBackend {
+		return backend.NewPodBackend(c)
+	}, func(context.Context, *trackingClient) {})
+})
+
+var _ = Describe("StatefulSetBackend", func() {
+	conformance(func(c client.Client) backend.WorkspaceBackend {
+		return backend.NewStatefulSetBackend(c)
+	}, syncStatefulSets)
+})
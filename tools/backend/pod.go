@@ -0,0 +1,51 @@
+package backend
+
+import (
+	"context"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/workspace"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PodBackend 直接创建Pod
+type PodBackend struct {
+	client client.Client
+}
+
+func NewPodBackend(c client.Client) *PodBackend {
+	return &PodBackend{client: c}
+}
+
+func (b *PodBackend) Start(ctx context.Context, pod *v1.Pod) error {
+	// 切换后端之前由StatefulSet创建的Pod仍在运行
+	if _, err := getPod(ctx, b.client, pod.Namespace, podName(pod.Name)); err == nil {
+		return apierrors.NewAlreadyExists(v1.Resource("pods"), pod.Name)
+	} else if !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	if err := b.client.Create(ctx, pod); err != nil {
+		return err
+	}
+
+	return setRunning(ctx, b.client, pod.Namespace, pod.Name)
+}
+
+func (b *PodBackend) Stop(ctx context.Context, namespace, name, reason string) error {
+	if err := setStopped(ctx, b.client, namespace, name); err != nil {
+		return err
+	}
+	pod, err := b.Get(ctx, namespace, name)
+	if err != nil {
+		return err
+	}
+
+	// 由StatefulSet管理的Pod通过缩容停止
+	return workspace.DeletePod(ctx, b.client, pod, reason)
+}
+
+func (b *PodBackend) Get(ctx context.Context, namespace, name string) (*v1.Pod, error) {
+	return getPod(ctx, b.client, namespace, name, podName(name))
+}
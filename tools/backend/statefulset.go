@@ -0,0 +1,117 @@
+package backend
+
+import (
+	"context"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/workspace"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//+kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
+
+// StatefulSetBackend 每个工作空间一个与其同名的单副本StatefulSet,Pod名称为<工作空间名称>-0
+type StatefulSetBackend struct {
+	client client.Client
+}
+
+func NewStatefulSetBackend(c client.Client) *StatefulSetBackend {
+	return &StatefulSetBackend{client: c}
+}
+
+func (b *StatefulSetBackend) Start(ctx context.Context, pod *v1.Pod) error {
+	if _, err := b.Get(ctx, pod.Namespace, pod.Name); err == nil {
+		return apierrors.NewAlreadyExists(v1.Resource("pods"), pod.Name)
+	} else if !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	desired := statefulSet(pod)
+	current := &appsv1.StatefulSet{}
+	err := b.client.Get(ctx, client.ObjectKeyFromObject(desired), current)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		if err := b.client.Create(ctx, desired); err != nil {
+			return err
+		}
+		return setRunning(ctx, b.client, pod.Namespace, pod.Name)
+	}
+
+	// 已经停止的工作空间,使用新的模板扩容,Pod的选择器不可修改,与工作空间名称绑定
+	current.Labels = desired.Labels
+	current.OwnerReferences = desired.OwnerReferences
+	current.Spec.Replicas = desired.Spec.Replicas
+	current.Spec.Template = desired.Spec.Template
+	if err := b.client.Update(ctx, current); err != nil {
+		return err
+	}
+
+	return setRunning(ctx, b.client, pod.Namespace, pod.Name)
+}
+
+func (b *StatefulSetBackend) Stop(ctx context.Context, namespace, name, reason string) error {
+	if err := setStopped(ctx, b.client, namespace, name); err != nil {
+		return err
+	}
+	pod, err := b.Get(ctx, namespace, name)
+	if err == nil {
+		return workspace.DeletePod(ctx, b.client, pod, reason)
+	}
+	if !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	// Pod被驱逐后还没有重建,副本数仍为1
+	sts := &appsv1.StatefulSet{}
+	if err := b.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, sts); err != nil {
+		return err
+	}
+	if sts.Spec.Replicas != nil && *sts.Spec.Replicas == 0 {
+		return apierrors.NewNotFound(v1.Resource("pods"), name)
+	}
+
+	return b.client.Patch(ctx, sts, client.RawPatch(types.MergePatchType, []byte(`{"spec":{"replicas":0}}`)))
+}
+
+func (b *StatefulSetBackend) Get(ctx context.Context, namespace, name string) (*v1.Pod, error) {
+	// 切换后端之前直接创建的Pod与工作空间同名
+	return getPod(ctx, b.client, namespace, podName(name), name)
+}
+
+func statefulSet(pod *v1.Pod) *appsv1.StatefulSet {
+	replicas := int32(1)
+	// 去掉StatefulSet控制器添加的标签和调度结果
+	tpl := workspace.PodTemplate(pod)
+	labels := tpl.Labels
+	labels[workspace.LabelWorkspace] = tpl.Name
+
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            pod.Name,
+			Namespace:       pod.Namespace,
+			Labels:          labels,
+			OwnerReferences: pod.OwnerReferences,
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:    &replicas,
+			ServiceName: pod.Name,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{workspace.LabelWorkspace: tpl.Name},
+			},
+			PodManagementPolicy: appsv1.ParallelPodManagement,
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      labels,
+					Annotations: pod.Annotations,
+				},
+				Spec: tpl.Spec,
+			},
+		},
+	}
+}
@@ -0,0 +1,345 @@
+package capacity
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+/*
+	容量估算: 模拟调度器的主要过滤条件(节点状态、nodeSelector、必需的节点亲和性、污点、资源),
+	计算一个Pod当前能够调度到多少个节点上,并检查命名空间的ResourceQuota。
+	节点从manager的缓存中读取,Pod需要统计所有命名空间,而缓存只包含工作空间的命名空间,因此直接从API Server读取,
+	读取的结果汇总为各节点的资源占用快照,在snapshotTTL内的估算共用同一个快照。
+	结果只是估算,没有考虑Pod间亲和性、拓扑分布和抢占
+*/
+
+//+kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=resourcequotas,verbs=get;list;watch
+
+// snapshotTTL 资源占用快照的有效期,刚调度的Pod最多延迟这么久才会计入估算
+const snapshotTTL = time.Second * 5
+
+// Report 容量估算结果
+type Report struct {
+	// 满足调度约束的节点数量
+	EligibleNodes int
+	// 满足调度约束且剩余资源足够的节点数量
+	FittingNodes int
+	// ResourceQuota是否允许创建该Pod
+	QuotaAllowed bool
+	// 不满足时的原因
+	Reasons []string
+}
+
+// Fits Pod当前是否可以启动
+func (r Report) Fits() bool {
+	return r.FittingNodes > 0 && r.QuotaAllowed
+}
+
+// Estimator 容量估算器,CheckCapacity和准入队列共用同一个估算器,从而共用资源占用快照
+type Estimator struct {
+	// 读取节点和ResourceQuota
+	cache client.Reader
+	// 读取所有命名空间的Pod
+	apiReader client.Reader
+
+	mu       sync.Mutex
+	snapshot *usage
+}
+
+// usage 各节点上已经调度且没有结束的Pod占用的资源,创建后只读
+type usage struct {
+	used     map[string]v1.ResourceList
+	podCount map[string]int64
+	takenAt  time.Time
+}
+
+func NewEstimator(cache client.Reader, apiReader client.Reader) *Estimator {
+	return &Estimator{
+		cache:     cache,
+		apiReader: apiReader,
+	}
+}
+
+// usage 返回资源占用快照,快照过期时重新读取,并发的调用方等待同一次读取
+func (e *Estimator) usage(ctx context.Context) (*usage, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.snapshot != nil && time.Since(e.snapshot.takenAt) < snapshotTTL {
+		return e.snapshot, nil
+	}
+
+	podList := &v1.PodList{}
+	selector := fields.AndSelectors(
+		fields.OneTermNotEqualSelector("spec.nodeName", ""),
+		fields.OneTermNotEqualSelector("status.phase", string(v1.PodSucceeded)),
+		fields.OneTermNotEqualSelector("status.phase", string(v1.PodFailed)),
+	)
+	if err := e.apiReader.List(ctx, podList, client.MatchingFieldsSelector{Selector: selector}); err != nil {
+		return nil, err
+	}
+	u := &usage{
+		used:     make(map[string]v1.ResourceList),
+		podCount: make(map[string]int64),
+		takenAt:  time.Now(),
+	}
+	for i := range podList.Items {
+		p := &podList.Items[i]
+		add(u.used, p.Spec.NodeName, PodRequests(p))
+		u.podCount[p.Spec.NodeName]++
+	}
+	e.snapshot = u
+
+	return u, nil
+}
+
+// Estimate 估算Pod当前是否可以启动
+func (e *Estimator) Estimate(ctx context.Context, pod *v1.Pod) (Report, error) {
+	report := Report{QuotaAllowed: true}
+
+	nodeList := &v1.NodeList{}
+	if err := e.cache.List(ctx, nodeList); err != nil {
+		return report, err
+	}
+	u, err := e.usage(ctx)
+	if err != nil {
+		return report, err
+	}
+	used, podCount := u.used, u.podCount
+
+	requests := PodRequests(pod)
+	insufficient := make(map[v1.ResourceName]bool)
+	for i := range nodeList.Items {
+		node := &nodeList.Items[i]
+		if !schedulable(node) || !matchNode(pod, node) || !toleratesNode(pod, node) {
+			continue
+		}
+		report.EligibleNodes++
+
+		free := node.Status.Allocatable.DeepCopy()
+		fits := podCount[node.Name] < node.Status.Allocatable.Pods().Value()
+		if !fits {
+			insufficient[v1.ResourcePods] = true
+		}
+		for name, q := range requests {
+			avail, ok := free[name]
+			if !ok {
+				fits = false
+				insufficient[name] = true
+				continue
+			}
+			u := used[node.Name][name]
+			avail.Sub(u)
+			if avail.Cmp(q) < 0 {
+				fits = false
+				insufficient[name] = true
+			}
+		}
+		if fits {
+			report.FittingNodes++
+		}
+	}
+
+	switch {
+	case report.EligibleNodes == 0:
+		report.Reasons = append(report.Reasons, "no node matches the scheduling profile")
+	case report.FittingNodes == 0:
+		names := make([]string, 0, len(insufficient))
+		for name := range insufficient {
+			names = append(names, string(name))
+		}
+		sort.Strings(names)
+		report.Reasons = append(report.Reasons, "insufficient "+strings.Join(names, ", "))
+	}
+
+	quotas := &v1.ResourceQuotaList{}
+	if err := e.cache.List(ctx, quotas, client.InNamespace(pod.Namespace)); err != nil {
+		return report, err
+	}
+	for _, quota := range quotas.Items {
+		if reason, ok := quotaAllows(&quota, pod, requests); !ok {
+			report.QuotaAllowed = false
+			report.Reasons = append(report.Reasons, reason)
+		}
+	}
+
+	return report, nil
+}
+
+// PodRequests Pod请求的资源,init容器按顺序执行,取其中的最大值与普通容器的总和比较
+func PodRequests(pod *v1.Pod) v1.ResourceList {
+	reqs := v1.ResourceList{}
+	for _, c := range pod.Spec.Containers {
+		for name, q := range c.Resources.Requests {
+			sum := reqs[name]
+			sum.Add(q)
+			reqs[name] = sum
+		}
+	}
+	for _, c := range pod.Spec.InitContainers {
+		for name, q := range c.Resources.Requests {
+			if cur, ok := reqs[name]; !ok || q.Cmp(cur) > 0 {
+				reqs[name] = q.DeepCopy()
+			}
+		}
+	}
+	for name, q := range pod.Spec.Overhead {
+		sum := reqs[name]
+		sum.Add(q)
+		reqs[name] = sum
+	}
+
+	return reqs
+}
+
+func add(used map[string]v1.ResourceList, node string, reqs v1.ResourceList) {
+	if used[node] == nil {
+		used[node] = v1.ResourceList{}
+	}
+	for name, q := range reqs {
+		sum := used[node][name]
+		sum.Add(q)
+		used[node][name] = sum
+	}
+}
+
+func schedulable(node *v1.Node) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == v1.NodeReady {
+			return cond.Status == v1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+// matchNode 检查nodeSelector和必需的节点亲和性
+func matchNode(pod *v1.Pod, node *v1.Node) bool {
+	if !labels.SelectorFromSet(pod.Spec.NodeSelector).Matches(labels.Set(node.Labels)) {
+		return false
+	}
+	affinity := pod.Spec.Affinity
+	if affinity == nil || affinity.NodeAffinity == nil || affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return true
+	}
+	// 多个term之间是或的关系
+	for _, term := range affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		if matchTerm(term, node) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchTerm(term v1.NodeSelectorTerm, node *v1.Node) bool {
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return false
+	}
+	for _, expr := range term.MatchExpressions {
+		req, err := requirement(expr)
+		if err != nil || !req.Matches(labels.Set(node.Labels)) {
+			return false
+		}
+	}
+	for _, expr := range term.MatchFields {
+		req, err := requirement(expr)
+		if err != nil || !req.Matches(labels.Set{"metadata.name": node.Name}) {
+			return false
+		}
+	}
+
+	return true
+}
+
+var operators = map[v1.NodeSelectorOperator]selection.Operator{
+	v1.NodeSelectorOpIn:           selection.In,
+	v1.NodeSelectorOpNotIn:        selection.NotIn,
+	v1.NodeSelectorOpExists:       selection.Exists,
+	v1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+	v1.NodeSelectorOpGt:           selection.GreaterThan,
+	v1.NodeSelectorOpLt:           selection.LessThan,
+}
+
+func requirement(expr v1.NodeSelectorRequirement) (*labels.Requirement, error) {
+	op, ok := operators[expr.Operator]
+	if !ok {
+		return nil, fmt.Errorf("unsupported operator %q", expr.Operator)
+	}
+
+	return labels.NewRequirement(expr.Key, op, expr.Values)
+}
+
+// toleratesNode Pod需要容忍节点上所有NoSchedule和NoExecute的污点
+func toleratesNode(pod *v1.Pod, node *v1.Node) bool {
+	for i := range node.Spec.Taints {
+		taint := &node.Spec.Taints[i]
+		if taint.Effect == v1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		tolerated := false
+		for j := range pod.Spec.Tolerations {
+			if pod.Spec.Tolerations[j].ToleratesTaint(taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			return false
+		}
+	}
+
+	return true
+}
+
+// quotaAllows 检查ResourceQuota剩余的额度,只检查Pod相关的资源
+func quotaAllows(quota *v1.ResourceQuota, pod *v1.Pod, requests v1.ResourceList) (string, bool) {
+	limits := v1.ResourceList{}
+	for _, c := range pod.Spec.Containers {
+		for name, q := range c.Resources.Limits {
+			sum := limits[name]
+			sum.Add(q)
+			limits[name] = sum
+		}
+	}
+	want := v1.ResourceList{
+		v1.ResourcePods: resource.MustParse("1"),
+	}
+	for name, q := range requests {
+		want[name] = q
+		want[v1.ResourceName("requests."+string(name))] = q
+	}
+	for name, q := range limits {
+		want[v1.ResourceName("limits."+string(name))] = q
+	}
+
+	for name, hard := range quota.Status.Hard {
+		q, ok := want[name]
+		if !ok {
+			continue
+		}
+		used := quota.Status.Used[name]
+		remain := hard.DeepCopy()
+		remain.Sub(used)
+		if remain.Cmp(q) < 0 {
+			return fmt.Sprintf("exceeded quota %s: %s, requested %s, used %s, limited %s",
+				quota.Name, name, q.String(), used.String(), hard.String()), false
+		}
+	}
+
+	return "", true
+}
@@ -0,0 +1,244 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/mangohow/cloud-ide-k8s-controller/conf"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+/*
+	镜像目录: 管理员在ConfigMap中维护允许使用的开发环境,每个环境包含镜像、展示名称、默认端口和默认资源,
+	镜像可以通过digest固定版本,没有固定时启动工作空间前将tag解析为digest,
+	工作空间使用 repo:tag@sha256:xxx 形式的镜像,digest优先,tag只用于展示和匹配预热池
+*/
+
+// DataKey ConfigMap中保存镜像列表的key
+const DataKey = "images.yaml"
+
+// Image 目录中的一个开发环境
+type Image struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"displayName"`
+	Description string `json:"description"`
+	// 镜像,repo:tag
+	Image string `json:"image"`
+	// 固定的digest,sha256:xxx,为空时解析tag
+	Digest string `json:"digest"`
+	Port   int32  `json:"port"`
+	// 调用方没有指定资源时使用的默认值
+	CPU     string `json:"cpu"`
+	Memory  string `json:"memory"`
+	Storage string `json:"storage"`
+	// 工作空间第一次启动时复制到存储卷中的目录(code-server的插件和配置),为空时使用全局配置
+	SeedDir string `json:"seedDir"`
+	// 包含种子目录的镜像,为空时从工作空间镜像中复制
+	SeedImage string `json:"seedImage"`
+}
+
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+
+// Catalog 镜像目录,未启用时为nil,所有方法都可以在nil上调用
+type Catalog struct {
+	client    client.Client
+	namespace string
+	cfg       conf.CatalogConfig
+
+	mu      sync.Mutex
+	digests map[string]resolved
+}
+
+type resolved struct {
+	digest string
+	at     time.Time
+}
+
+func NewCatalog(client client.Client, namespace string, cfg conf.CatalogConfig) *Catalog {
+	return &Catalog{
+		client:    client,
+		namespace: namespace,
+		cfg:       cfg,
+		digests:   make(map[string]resolved),
+	}
+}
+
+// List 返回目录中的所有镜像
+func (c *Catalog) List(ctx context.Context) ([]Image, error) {
+	if c == nil {
+		return nil, nil
+	}
+	cm := &v1.ConfigMap{}
+	if err := c.client.Get(ctx, client.ObjectKey{Name: c.cfg.ConfigMap, Namespace: c.namespace}, cm); err != nil {
+		return nil, err
+	}
+
+	var images []Image
+	if err := yaml.UnmarshalStrict([]byte(cm.Data[DataKey]), &images); err != nil {
+		return nil, fmt.Errorf("parse image catalog: %v", err)
+	}
+	for _, img := range images {
+		if err := validate(img); err != nil {
+			return nil, fmt.Errorf("invalid catalog image %q: %v", img.ID, err)
+		}
+	}
+
+	return images, nil
+}
+
+// Get 根据ID查找镜像
+func (c *Catalog) Get(ctx context.Context, id string) (Image, bool, error) {
+	images, err := c.List(ctx)
+	if err != nil {
+		return Image{}, false, err
+	}
+	for _, img := range images {
+		if img.ID == id {
+			return img, true, nil
+		}
+	}
+
+	return Image{}, false, nil
+}
+
+// Find 查找镜像对应的目录项,image可以是目录中的tag或固定后的镜像
+func (c *Catalog) Find(ctx context.Context, image string) (Image, bool, error) {
+	images, err := c.List(ctx)
+	if err != nil {
+		return Image{}, false, err
+	}
+	for _, img := range images {
+		if img.Image == image || img.Image == Unpinned(image) {
+			return img, true, nil
+		}
+	}
+
+	return Image{}, false, nil
+}
+
+// Allowed 镜像是否在白名单中,白名单项以*结尾时按前缀匹配
+func (c *Catalog) Allowed(image string) bool {
+	if c == nil {
+		return true
+	}
+	image = Unpinned(image)
+	for _, pattern := range c.cfg.AllowedImages {
+		if strings.HasSuffix(pattern, "*") && strings.HasPrefix(image, strings.TrimSuffix(pattern, "*")) {
+			return true
+		}
+		if pattern == image {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Pin 返回固定了digest的镜像,目录项有digest时直接使用,否则解析tag
+func (c *Catalog) Pin(ctx context.Context, img Image) string {
+	if img.Digest != "" {
+		return Unpinned(img.Image) + "@" + img.Digest
+	}
+
+	return c.Resolve(ctx, img.Image)
+}
+
+// Resolve 将tag解析为digest,返回 repo:tag@sha256:xxx,
+// 解析结果缓存DigestCacheTTL时间,镜像仓库不可用时使用上一次的结果,都没有时返回原镜像
+func (c *Catalog) Resolve(ctx context.Context, image string) string {
+	if c == nil || !c.cfg.ResolveDigest || strings.Contains(image, "@") {
+		return image
+	}
+
+	c.mu.Lock()
+	last, ok := c.digests[image]
+	c.mu.Unlock()
+	if ok && time.Since(last.at) < c.cfg.DigestCacheTTL.Duration {
+		return image + "@" + last.digest
+	}
+
+	digest, err := resolveDigest(ctx, image)
+	if err != nil {
+		klog.Warningf("resolve image digest error:%v, image:%s", err, image)
+		if ok {
+			return image + "@" + last.digest
+		}
+		return image
+	}
+	c.mu.Lock()
+	c.digests[image] = resolved{digest: digest, at: time.Now()}
+	c.mu.Unlock()
+
+	return image + "@" + digest
+}
+
+// Images 返回目录中所有固定了digest的镜像以及种子镜像,用于预拉取
+func (c *Catalog) Images(ctx context.Context) ([]string, error) {
+	images, err := c.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	refs := make([]string, 0, len(images))
+	for _, img := range images {
+		refs = append(refs, c.Pin(ctx, img))
+		if img.SeedImage != "" {
+			refs = append(refs, img.SeedImage)
+		}
+	}
+
+	return refs, nil
+}
+
+// Unpinned 去掉镜像中的digest
+func Unpinned(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		return image[:i]
+	}
+
+	return image
+}
+
+func resolveDigest(ctx context.Context, image string) (string, error) {
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return "", err
+	}
+	desc, err := remote.Head(ref, remote.WithContext(ctx), remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	if err != nil {
+		return "", err
+	}
+
+	return desc.Digest.String(), nil
+}
+
+func validate(img Image) error {
+	if img.ID == "" || img.Image == "" {
+		return fmt.Errorf("id and image are required")
+	}
+	if strings.Contains(img.Image, "@") {
+		return fmt.Errorf("image must not contain digest, use the digest field")
+	}
+	if img.Digest != "" && !strings.HasPrefix(img.Digest, "sha256:") {
+		return fmt.Errorf("digest must start with sha256:")
+	}
+	for _, q := range []string{img.CPU, img.Memory, img.Storage} {
+		if q == "" {
+			continue
+		}
+		if _, err := resource.ParseQuantity(q); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,327 @@
+package devcontainer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/*
+	devcontainer.json支持: 只转换工作空间Pod可以表达的部分,
+	image、containerEnv、forwardPorts、mounts、postCreateCommand和remoteUser,
+	其它属性被忽略并作为警告返回给调用方。devcontainer.json允许注释和末尾逗号,解析前先去除
+*/
+
+// 挂载类型
+const (
+	MountVolume = "volume"
+	MountTmpfs  = "tmpfs"
+	MountBind   = "bind"
+)
+
+// Spec devcontainer.json中支持的部分
+type Spec struct {
+	Image        string
+	ContainerEnv map[string]string
+	ForwardPorts []int32
+	Mounts       []Mount
+	// 转换后的shell命令,为空时没有postCreateCommand
+	PostCreateCommand string
+	RemoteUser        string
+}
+
+type Mount struct {
+	Type   string
+	Source string
+	Target string
+}
+
+// ignored 不影响工作空间的属性,忽略时不产生警告
+var ignored = map[string]bool{
+	"$schema": true,
+	"name":    true,
+}
+
+// Parse 解析devcontainer.json,属性类型不正确时返回错误,不支持的属性作为警告返回
+func Parse(data []byte) (*Spec, []string, error) {
+	var props map[string]json.RawMessage
+	if err := json.Unmarshal(Normalize(data), &props); err != nil {
+		return nil, nil, fmt.Errorf("parse devcontainer.json: %v", err)
+	}
+
+	keys := make([]string, 0, len(props))
+	for key := range props {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	spec := &Spec{}
+	var warnings []string
+	for _, key := range keys {
+		raw := props[key]
+		var err error
+		switch key {
+		case "image":
+			err = json.Unmarshal(raw, &spec.Image)
+		case "containerEnv":
+			err = json.Unmarshal(raw, &spec.ContainerEnv)
+		case "remoteUser":
+			err = json.Unmarshal(raw, &spec.RemoteUser)
+		case "forwardPorts":
+			var w []string
+			spec.ForwardPorts, w, err = parsePorts(raw)
+			warnings = append(warnings, w...)
+		case "mounts":
+			var w []string
+			spec.Mounts, w, err = parseMounts(raw)
+			warnings = append(warnings, w...)
+		case "postCreateCommand":
+			spec.PostCreateCommand, err = parseCommand(raw)
+		default:
+			if !ignored[key] {
+				warnings = append(warnings, fmt.Sprintf("unsupported property %q ignored", key))
+			}
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid %s: %v", key, err)
+		}
+	}
+
+	return spec, warnings, nil
+}
+
+// Compact 校验devcontainer.json并返回去除注释和空白后的内容,用于保存在注解中
+func Compact(data []byte) (string, error) {
+	if _, _, err := Parse(data); err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, Normalize(data)); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// Normalize 去除注释和末尾逗号,返回标准JSON
+func Normalize(data []byte) []byte {
+	var out bytes.Buffer
+	inString := false
+	for i := 0; i < len(data); i++ {
+		ch := data[i]
+		if inString {
+			out.WriteByte(ch)
+			if ch == '\\' && i+1 < len(data) {
+				i++
+				out.WriteByte(data[i])
+			} else if ch == '"' {
+				inString = false
+			}
+			continue
+		}
+		switch {
+		case ch == '"':
+			inString = true
+			out.WriteByte(ch)
+		case ch == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			if i < len(data) {
+				out.WriteByte('\n')
+			}
+		case ch == '/' && i+1 < len(data) && data[i+1] == '*':
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				i = len(data)
+			} else {
+				i += end + 3
+			}
+		case ch == ',' && closesNext(data[i+1:]):
+			// 末尾逗号
+		default:
+			out.WriteByte(ch)
+		}
+	}
+
+	return out.Bytes()
+}
+
+// closesNext 逗号之后(跳过空白和注释)是否是 } 或 ]
+func closesNext(data []byte) bool {
+	for i := 0; i < len(data); i++ {
+		switch {
+		case data[i] == ' ' || data[i] == '\t' || data[i] == '\n' || data[i] == '\r':
+		case data[i] == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+		case data[i] == '/' && i+1 < len(data) && data[i+1] == '*':
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				return false
+			}
+			i += end + 3
+		default:
+			return data[i] == '}' || data[i] == ']'
+		}
+	}
+
+	return false
+}
+
+// parsePorts 端口可以是数字或"host:port",其它主机上的端口无法转发
+func parsePorts(raw json.RawMessage) ([]int32, []string, error) {
+	var items []interface{}
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, nil, err
+	}
+	var (
+		ports    []int32
+		warnings []string
+	)
+	for _, item := range items {
+		var port string
+		switch v := item.(type) {
+		case float64:
+			port = strconv.FormatFloat(v, 'f', -1, 64)
+		case string:
+			port = v
+			if i := strings.LastIndex(v, ":"); i >= 0 {
+				host := v[:i]
+				if host != "localhost" && host != "127.0.0.1" {
+					warnings = append(warnings, fmt.Sprintf("forwardPorts %q on another host ignored", v))
+					continue
+				}
+				port = v[i+1:]
+			}
+		default:
+			return nil, nil, fmt.Errorf("port must be a number or string")
+		}
+		p, err := strconv.ParseInt(port, 10, 32)
+		if err != nil || p < 1 || p > 65535 {
+			return nil, nil, fmt.Errorf("invalid port %v", item)
+		}
+		ports = append(ports, int32(p))
+	}
+
+	return ports, warnings, nil
+}
+
+// parseMounts 挂载可以是"type=volume,source=x,target=/y"形式的字符串或对象,bind挂载无法在集群中使用
+func parseMounts(raw json.RawMessage) ([]Mount, []string, error) {
+	var items []interface{}
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, nil, err
+	}
+	var (
+		mounts   []Mount
+		warnings []string
+	)
+	for _, item := range items {
+		m := Mount{Type: MountVolume}
+		switch v := item.(type) {
+		case string:
+			for _, part := range strings.Split(v, ",") {
+				kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+				if len(kv) != 2 {
+					continue
+				}
+				setMountField(&m, kv[0], kv[1])
+			}
+		case map[string]interface{}:
+			for k, val := range v {
+				if s, ok := val.(string); ok {
+					setMountField(&m, k, s)
+				}
+			}
+		default:
+			return nil, nil, fmt.Errorf("mount must be a string or object")
+		}
+
+		switch {
+		case m.Type == MountBind:
+			warnings = append(warnings, fmt.Sprintf("bind mount to %s ignored", m.Target))
+			continue
+		case m.Type != MountVolume && m.Type != MountTmpfs:
+			warnings = append(warnings, fmt.Sprintf("mount type %q ignored", m.Type))
+			continue
+		case !path.IsAbs(m.Target):
+			return nil, nil, fmt.Errorf("mount target %q must be an absolute path", m.Target)
+		case m.Type == MountVolume && (m.Source == "" || strings.ContainsAny(m.Source, "/\\") || m.Source == ".."):
+			return nil, nil, fmt.Errorf("invalid volume name %q", m.Source)
+		}
+		m.Target = path.Clean(m.Target)
+		mounts = append(mounts, m)
+	}
+
+	return mounts, warnings, nil
+}
+
+func setMountField(m *Mount, key, value string) {
+	switch key {
+	case "type":
+		m.Type = value
+	case "source", "src":
+		m.Source = value
+	case "target", "destination", "dst":
+		m.Target = value
+	}
+}
+
+// parseCommand 命令可以是shell字符串、参数数组,或多个并行执行的命令组成的对象
+func parseCommand(raw json.RawMessage) (string, error) {
+	var v interface{}
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return "", err
+	}
+	if obj, ok := v.(map[string]interface{}); ok {
+		names := make([]string, 0, len(obj))
+		for name := range obj {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		var parts []string
+		for _, name := range names {
+			cmd, err := command(obj[name])
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, "("+cmd+") &")
+		}
+		if len(parts) == 0 {
+			return "", nil
+		}
+		return strings.Join(parts, " ") + " wait", nil
+	}
+
+	return command(v)
+}
+
+func command(v interface{}) (string, error) {
+	switch cmd := v.(type) {
+	case string:
+		return cmd, nil
+	case []interface{}:
+		args := make([]string, 0, len(cmd))
+		for _, arg := range cmd {
+			s, ok := arg.(string)
+			if !ok {
+				return "", fmt.Errorf("command arguments must be strings")
+			}
+			args = append(args, quote(s))
+		}
+		return strings.Join(args, " "), nil
+	default:
+		return "", fmt.Errorf("command must be a string, array or object")
+	}
+}
+
+// quote 将参数转为单引号括起来的shell字符串
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
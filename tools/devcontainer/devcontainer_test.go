@@ -0,0 +1,93 @@
+package devcontainer_test
+
+import (
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/devcontainer"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Normalize", func() {
+	DescribeTable("removes comments and trailing commas",
+		func(input, expected string) {
+			Expect(string(devcontainer.Normalize([]byte(input)))).To(MatchJSON(expected))
+		},
+		Entry("line and block comments", "{ // comment\n \"a\": 1 /* block */ }", `{"a":1}`),
+		Entry("comment at the end without newline", "{\"a\": 1}\n// end", `{"a":1}`),
+		Entry("// inside a string", `{"url": "http://example.com//path"}`, `{"url":"http://example.com//path"}`),
+		Entry("/* and escaped quote inside a string", `{"s": "a\"/*b*/"}`, `{"s":"a\"/*b*/"}`),
+		Entry("trailing commas", `{"a": [1, 2,], "b": {"c": 1,},}`, `{"a":[1,2],"b":{"c":1}}`),
+		Entry("trailing comma before a comment", "{\"a\": 1, // comment\n}", `{"a":1}`),
+		Entry("comma and brace inside a string", `{"a": ",}", "b": 2}`, `{"a":",}","b":2}`),
+	)
+})
+
+var _ = Describe("Parse mounts", func() {
+	parse := func(mounts string) ([]devcontainer.Mount, []string, error) {
+		spec, warnings, err := devcontainer.Parse([]byte(`{"mounts": ` + mounts + `}`))
+		if err != nil {
+			return nil, nil, err
+		}
+		return spec.Mounts, warnings, nil
+	}
+
+	DescribeTable("supported mounts",
+		func(mounts string, expected []devcontainer.Mount, warnings []string) {
+			m, w, err := parse(mounts)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(m).To(Equal(expected))
+			Expect(w).To(Equal(warnings))
+		},
+		Entry("volume string",
+			`["source=cache,target=/root/.cache,type=volume"]`,
+			[]devcontainer.Mount{{Type: devcontainer.MountVolume, Source: "cache", Target: "/root/.cache"}}, nil),
+		Entry("volume is the default type with short keys",
+			`["src=data, dst=/data/"]`,
+			[]devcontainer.Mount{{Type: devcontainer.MountVolume, Source: "data", Target: "/data"}}, nil),
+		Entry("tmpfs object",
+			`[{"type": "tmpfs", "target": "/tmp/build"}]`,
+			[]devcontainer.Mount{{Type: devcontainer.MountTmpfs, Target: "/tmp/build"}}, nil),
+		Entry("bind mount ignored",
+			`["type=bind,source=/var/run/docker.sock,target=/var/run/docker.sock"]`,
+			nil, []string{"bind mount to /var/run/docker.sock ignored"}),
+		Entry("unknown type ignored",
+			`["type=npipe,source=x,target=/x"]`,
+			nil, []string{`mount type "npipe" ignored`}),
+	)
+
+	DescribeTable("invalid mounts",
+		func(mounts, message string) {
+			_, _, err := parse(mounts)
+			Expect(err).To(MatchError(ContainSubstring(message)))
+		},
+		Entry("relative target", `["source=cache,target=cache"]`, "must be an absolute path"),
+		Entry("volume name with a slash", `["source=../cache,target=/cache"]`, "invalid volume name"),
+		Entry("volume name ..", `["source=..,target=/cache"]`, "invalid volume name"),
+		Entry("volume without a name", `["target=/cache"]`, "invalid volume name"),
+		Entry("mount is not a string or object", `[1]`, "mount must be a string or object"),
+	)
+})
+
+var _ = Describe("Parse postCreateCommand", func() {
+	DescribeTable("converts the command to a shell command",
+		func(command, expected string) {
+			spec, _, err := devcontainer.Parse([]byte(`{"postCreateCommand": ` + command + `}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spec.PostCreateCommand).To(Equal(expected))
+		},
+		Entry("shell string", `"npm install && npm run build"`, "npm install && npm run build"),
+		Entry("array arguments are quoted", `["echo", "it's", "$HOME"]`, `'echo' 'it'\''s' '$HOME'`),
+		Entry("object commands run in parallel", `{"build": "make", "deps": ["go", "mod", "download"]}`,
+			`(make) & ('go' 'mod' 'download') & wait`),
+		Entry("empty object", `{}`, ""),
+	)
+
+	DescribeTable("invalid commands",
+		func(command string) {
+			_, _, err := devcontainer.Parse([]byte(`{"postCreateCommand": ` + command + `}`))
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("number", `1`),
+		Entry("array with a non-string argument", `["echo", 1]`),
+		Entry("object with an invalid command", `{"a": true}`),
+	)
+})
@@ -0,0 +1,157 @@
+package devcontainer
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// EnvPostCreateCommand 保存postCreateCommand的环境变量,由postStart钩子读取,避免命令被再次转义
+	EnvPostCreateCommand = "CLOUD_IDE_POST_CREATE_COMMAND"
+	// 存储卷中保存命名卷的目录,命名卷随工作空间一起持久化
+	volumesDir = ".devcontainer/volumes"
+	// postCreateCommand执行成功后写入的标记文件和输出日志
+	postCreateMarker = ".devcontainer/post-create.done"
+	postCreateLog    = ".devcontainer/post-create.log"
+)
+
+// postStartScript 标记文件不存在时在后台执行postCreateCommand,不阻塞容器启动,执行成功后写入标记文件,
+// 因此只执行一次,失败时下次启动重试
+func postStartScript(dir string) string {
+	return fmt.Sprintf(`cd %[1]s || exit 0
+[ -f %[2]s ] && exit 0
+mkdir -p .devcontainer
+nohup sh -c 'sh -c "$%[4]s" > %[3]s 2>&1 && touch %[2]s' > /dev/null 2>&1 &`,
+		quote(dir), postCreateMarker, postCreateLog, EnvPostCreateCommand)
+}
+
+// Apply 将spec转换到工作空间容器上,image由调用方处理;volume和mountPath为工作空间存储卷及其挂载路径,
+// remoteUser只能使用管理员允许的uid
+func Apply(pod *v1.Pod, container *v1.Container, spec *Spec, volume, mountPath string, allowedUIDs []int64) []string {
+	var warnings []string
+
+	for _, name := range sortedKeys(spec.ContainerEnv) {
+		setEnv(container, name, spec.ContainerEnv[name])
+	}
+
+	for _, port := range spec.ForwardPorts {
+		if hasPort(container, port) {
+			continue
+		}
+		container.Ports = append(container.Ports, v1.ContainerPort{
+			Name:          fmt.Sprintf("fwd-%d", port),
+			ContainerPort: port,
+		})
+	}
+
+	for i, m := range spec.Mounts {
+		if hasMountPath(container, m.Target) {
+			warnings = append(warnings, fmt.Sprintf("mount target %s is already mounted, ignored", m.Target))
+			continue
+		}
+		switch m.Type {
+		case MountVolume:
+			container.VolumeMounts = append(container.VolumeMounts, v1.VolumeMount{
+				Name:      volume,
+				MountPath: m.Target,
+				SubPath:   volumesDir + "/" + m.Source,
+			})
+		case MountTmpfs:
+			name := fmt.Sprintf("devcontainer-tmpfs-%d", i)
+			pod.Spec.Volumes = append(pod.Spec.Volumes, v1.Volume{
+				Name:         name,
+				VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{Medium: v1.StorageMediumMemory}},
+			})
+			container.VolumeMounts = append(container.VolumeMounts, v1.VolumeMount{Name: name, MountPath: m.Target})
+		}
+	}
+
+	if spec.PostCreateCommand != "" {
+		if container.Lifecycle != nil && container.Lifecycle.PostStart != nil {
+			warnings = append(warnings, "container already has a postStart hook, postCreateCommand ignored")
+		} else {
+			setEnv(container, EnvPostCreateCommand, spec.PostCreateCommand)
+			if container.Lifecycle == nil {
+				container.Lifecycle = &v1.Lifecycle{}
+			}
+			container.Lifecycle.PostStart = &v1.LifecycleHandler{
+				Exec: &v1.ExecAction{Command: []string{"sh", "-c", postStartScript(mountPath)}},
+			}
+		}
+	}
+
+	if spec.RemoteUser != "" {
+		// Kubernetes只能以uid指定用户
+		uid, err := strconv.ParseInt(spec.RemoteUser, 10, 64)
+		if spec.RemoteUser == "root" {
+			uid, err = 0, nil
+		}
+		if err != nil || uid < 0 {
+			warnings = append(warnings, fmt.Sprintf("remoteUser %q is not a numeric uid, ignored", spec.RemoteUser))
+		} else if !allowed(allowedUIDs, uid) {
+			warnings = append(warnings, fmt.Sprintf("remoteUser %q is not allowed by the administrator, ignored", spec.RemoteUser))
+		} else {
+			if container.SecurityContext == nil {
+				container.SecurityContext = &v1.SecurityContext{}
+			}
+			container.SecurityContext.RunAsUser = &uid
+		}
+	}
+
+	return warnings
+}
+
+func allowed(uids []int64, uid int64) bool {
+	for _, u := range uids {
+		if u == uid {
+			return true
+		}
+	}
+
+	return false
+}
+
+func setEnv(container *v1.Container, name, value string) {
+	for i := range container.Env {
+		if container.Env[i].Name == name {
+			container.Env[i].Value = value
+			container.Env[i].ValueFrom = nil
+			return
+		}
+	}
+	container.Env = append(container.Env, v1.EnvVar{Name: name, Value: value})
+}
+
+func hasPort(container *v1.Container, port int32) bool {
+	for _, p := range container.Ports {
+		if p.ContainerPort == port {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasMountPath(container *v1.Container, target string) bool {
+	for _, m := range container.VolumeMounts {
+		if strings.TrimSuffix(m.MountPath, "/") == target {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,33 @@
+package devcontainer_test
+
+import (
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/devcontainer"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("Apply remoteUser", func() {
+	uid := func(v int64) *int64 { return &v }
+
+	DescribeTable("only allowed uids are used",
+		func(remoteUser string, allowedUIDs []int64, expected *int64, warnings []string) {
+			pod := &v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{{Name: "ws"}}}}
+			w := devcontainer.Apply(pod, &pod.Spec.Containers[0], &devcontainer.Spec{RemoteUser: remoteUser}, "data", "/user_data", allowedUIDs)
+			Expect(w).To(Equal(warnings))
+			if expected == nil {
+				Expect(pod.Spec.Containers[0].SecurityContext).To(BeNil())
+			} else {
+				Expect(pod.Spec.Containers[0].SecurityContext.RunAsUser).To(Equal(expected))
+			}
+		},
+		Entry("allowed uid", "1000", []int64{1000}, uid(1000), nil),
+		Entry("root allowed as uid 0", "root", []int64{0, 1000}, uid(0), nil),
+		Entry("root without opt-in", "root", []int64{1000}, nil,
+			[]string{`remoteUser "root" is not allowed by the administrator, ignored`}),
+		Entry("no allowed uids", "1000", nil, nil,
+			[]string{`remoteUser "1000" is not allowed by the administrator, ignored`}),
+		Entry("user name", "vscode", []int64{1000}, nil,
+			[]string{`remoteUser "vscode" is not a numeric uid, ignored`}),
+	)
+})
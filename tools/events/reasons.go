@@ -0,0 +1,47 @@
+package events
+
+// 工作空间相关的Kubernetes Event原因,可以通过kubectl describe查看
+const (
+	ReasonPVCCreated   = "PVCCreated"
+	ReasonPVCDeleted   = "PVCDeleted"
+	ReasonPodCreated   = "PodCreated"
+	ReasonPodReady     = "PodReady"
+	ReasonPodDeleted   = "PodDeleted"
+	ReasonStartTimeout = "StartTimeout"
+	// 回收站
+	ReasonWorkspaceTrashed  = "WorkspaceTrashed"
+	ReasonWorkspaceRestored = "WorkspaceRestored"
+	ReasonWorkspacePurged   = "WorkspacePurged"
+	// 垃圾回收发现的问题资源
+	ReasonOrphanDetected   = "OrphanDetected"
+	ReasonGarbageCollected = "GarbageCollected"
+	// 镜像升级
+	ReasonUpgradeScheduled  = "UpgradeScheduled"
+	ReasonWorkspaceUpgraded = "WorkspaceUpgraded"
+	// 自动恢复
+	ReasonWorkspaceHealed = "WorkspaceHealed"
+	ReasonHealGaveUp      = "HealGaveUp"
+	// 节点维护
+	ReasonDrainNotice       = "DrainNotice"
+	ReasonWorkspaceMigrated = "WorkspaceMigrated"
+	// Git仓库克隆
+	ReasonGitCloned      = "GitCloned"
+	ReasonGitCloneFailed = "GitCloneFailed"
+	// devcontainer.json
+	ReasonDevcontainerLoaded  = "DevcontainerLoaded"
+	ReasonDevcontainerInvalid = "DevcontainerInvalid"
+	// execSpace审计
+	ReasonExecStarted  = "ExecStarted"
+	ReasonExecFinished = "ExecFinished"
+	// 超时或调用方断开后被终止
+	ReasonExecTerminated = "ExecTerminated"
+)
+
+// 失败原因,与service中返回给grpc调用方的错误一一对应
+const (
+	ReasonConstructPVCFailed = "ConstructPVCFailed"
+	ReasonCreatePVCFailed    = "CreatePVCFailed"
+	ReasonCreatePodFailed    = "CreatePodFailed"
+	ReasonDeletePodFailed    = "DeletePodFailed"
+	ReasonDeletePVCFailed    = "DeletePVCFailed"
+)
@@ -0,0 +1,52 @@
+package podexec
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Authenticator 使用Secret中保存的令牌认证操作人,Secret的key为操作人,value为令牌。
+// 只有在operators中的操作人可以执行,operators为空时拒绝所有操作人
+type Authenticator struct {
+	reader    client.Reader
+	namespace string
+	secret    string
+	operators map[string]bool
+}
+
+func NewAuthenticator(reader client.Reader, namespace, secret string, operators []string) *Authenticator {
+	allowed := make(map[string]bool, len(operators))
+	for _, op := range operators {
+		allowed[op] = true
+	}
+
+	return &Authenticator{
+		reader:    reader,
+		namespace: namespace,
+		secret:    secret,
+		operators: allowed,
+	}
+}
+
+func (a *Authenticator) Authenticate(ctx context.Context, operator, token string) error {
+	if !a.operators[operator] {
+		return fmt.Errorf("operator %s is not in the allowed operators", operator)
+	}
+	secret := &v1.Secret{}
+	if err := a.reader.Get(ctx, client.ObjectKey{Namespace: a.namespace, Name: a.secret}, secret); err != nil {
+		return err
+	}
+	expected, ok := secret.Data[operator]
+	// 使用kubectl create secret --from-file创建时令牌末尾可能有换行
+	expected = bytes.TrimSpace(expected)
+	if !ok || len(expected) == 0 || subtle.ConstantTimeCompare(expected, []byte(token)) != 1 {
+		return fmt.Errorf("invalid token")
+	}
+
+	return nil
+}
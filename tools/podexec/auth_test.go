@@ -0,0 +1,53 @@
+package podexec_test
+
+import (
+	"context"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/podexec"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Authenticator", func() {
+	const (
+		namespace  = "cloud-ide-system"
+		secretName = "cloud-ide-exec-tokens"
+	)
+
+	DescribeTable("Authenticate",
+		func(tokens map[string]string, operators []string, operator, token string, allowed bool) {
+			builder := fake.NewClientBuilder().WithScheme(scheme.Scheme)
+			if tokens != nil {
+				data := make(map[string][]byte, len(tokens))
+				for k, v := range tokens {
+					data[k] = []byte(v)
+				}
+				builder = builder.WithObjects(&v1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: namespace},
+					Data:       data,
+				})
+			}
+			a := podexec.NewAuthenticator(builder.Build(), namespace, secretName, operators)
+
+			err := a.Authenticate(context.Background(), operator, token)
+			if allowed {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(HaveOccurred())
+			}
+		},
+		Entry("valid token", map[string]string{"alice": "s3cr3t"}, []string{"alice"}, "alice", "s3cr3t", true),
+		Entry("stored token with a trailing newline", map[string]string{"alice": "s3cr3t\n"}, []string{"alice"}, "alice", "s3cr3t", true),
+		Entry("wrong token", map[string]string{"alice": "s3cr3t"}, []string{"alice"}, "alice", "other", false),
+		Entry("caller token with a trailing newline", map[string]string{"alice": "s3cr3t"}, []string{"alice"}, "alice", "s3cr3t\n", false),
+		Entry("operator not in the list", map[string]string{"alice": "s3cr3t", "bob": "b0b"}, []string{"alice"}, "bob", "b0b", false),
+		Entry("no operators allowed", map[string]string{"alice": "s3cr3t"}, nil, "alice", "s3cr3t", false),
+		Entry("operator without a token", map[string]string{"bob": "b0b"}, []string{"alice"}, "alice", "", false),
+		Entry("blank stored token", map[string]string{"alice": " \n"}, []string{"alice"}, "alice", "", false),
+		Entry("token secret missing", nil, []string{"alice"}, "alice", "s3cr3t", false),
+	)
+})
@@ -0,0 +1,124 @@
+package podexec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes/scheme"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/transport/spdy"
+	"k8s.io/client-go/util/exec"
+)
+
+//+kubebuilder:rbac:groups="",resources=pods/exec,verbs=create
+
+// Executor 通过Pod的exec子资源在容器中执行命令,使用SPDY协议
+type Executor struct {
+	config *rest.Config
+	client rest.Interface
+}
+
+func NewExecutor(config *rest.Config) (*Executor, error) {
+	c, err := corev1client.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Executor{config: config, client: c.RESTClient()}, nil
+}
+
+// Session 一次命令执行,Stdin为nil时不连接标准输入,TTY时标准错误合并在Stdout中
+type Session struct {
+	Namespace string
+	Pod       string
+	Container string
+	Command   []string
+	TTY       bool
+	Stdin     io.Reader
+	Stdout    io.Writer
+	Stderr    io.Writer
+	// 终端大小变化,只在TTY时使用
+	Resize remotecommand.TerminalSizeQueue
+}
+
+// ErrTerminated 会话因为上下文结束(超时或调用方断开)被终止
+var ErrTerminated = errors.New("session terminated")
+
+// Exec 执行命令直到结束,返回命令的退出码,命令以非0退出不作为错误。
+// ctx结束时关闭与API Server的连接终止会话,返回包装了ctx.Err()的ErrTerminated
+func (e *Executor) Exec(ctx context.Context, s *Session) (int, error) {
+	req := e.client.Post().
+		Resource("pods").
+		Namespace(s.Namespace).
+		Name(s.Pod).
+		SubResource("exec").
+		VersionedParams(&v1.PodExecOptions{
+			Container: s.Container,
+			Command:   s.Command,
+			Stdin:     s.Stdin != nil,
+			Stdout:    true,
+			Stderr:    !s.TTY,
+			TTY:       s.TTY,
+		}, scheme.ParameterCodec)
+	transport, upgrader, err := spdy.RoundTripperFor(e.config)
+	if err != nil {
+		return 0, err
+	}
+	executor, err := remotecommand.NewSPDYExecutorForTransports(transport, &cancelableUpgrader{Upgrader: upgrader, ctx: ctx}, "POST", req.URL())
+	if err != nil {
+		return 0, err
+	}
+
+	opts := remotecommand.StreamOptions{
+		Stdin:  s.Stdin,
+		Stdout: s.Stdout,
+		Tty:    s.TTY,
+	}
+	if s.TTY {
+		opts.TerminalSizeQueue = s.Resize
+	} else {
+		opts.Stderr = s.Stderr
+	}
+	err = executor.Stream(opts)
+	if ctx.Err() != nil {
+		return 0, fmt.Errorf("%w: %v", ErrTerminated, ctx.Err())
+	}
+	var exitErr exec.CodeExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return 0, nil
+}
+
+// cancelableUpgrader 在ctx结束时关闭升级后的SPDY连接,remotecommand不支持取消,关闭连接后Stream返回
+type cancelableUpgrader struct {
+	spdy.Upgrader
+	ctx context.Context
+}
+
+func (u *cancelableUpgrader) NewConnection(resp *http.Response) (httpstream.Connection, error) {
+	conn, err := u.Upgrader.NewConnection(resp)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		select {
+		case <-u.ctx.Done():
+			conn.Close()
+		case <-conn.CloseChan():
+		}
+	}()
+
+	return conn, nil
+}
@@ -0,0 +1,14 @@
+package podexec_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestPodExec(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "PodExec Suite")
+}
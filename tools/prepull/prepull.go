@@ -0,0 +1,288 @@
+package prepull
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/conf"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/catalog"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+/*
+	镜像预拉取: 控制器维护一个DaemonSet,每个镜像对应一个常驻容器,容器只执行sleep,
+	kubelet启动容器前会拉取镜像,每个容器独立拉取,某个镜像拉取失败不影响其它镜像,因此预拉取的镜像中需要包含sh和sleep。
+	需要预拉取的镜像为镜像目录中固定了digest的镜像和配置中的额外镜像,
+	镜像列表变化时更新DaemonSet,滚动更新后从列表中移除的镜像不再被引用,由kubelet的镜像回收清理
+*/
+
+const (
+	// Name 预拉取DaemonSet的名称
+	Name = "cloud-ide-image-prepuller"
+	// LabelApp 预拉取Pod的标签,与工作空间和预热Pod区分
+	LabelApp = "app"
+	// AnnotationHash 镜像列表和节点选择器的哈希,用于判断DaemonSet是否需要更新
+	AnnotationHash = "cloud-ide.mangohow.com/prepull-hash"
+
+	containerPrefix = "pull-"
+)
+
+// 镜像在节点上的拉取状态
+const (
+	StatePending = "Pending"
+	StatePulling = "Pulling"
+	StatePulled  = "Pulled"
+	StateFailed  = "Failed"
+)
+
+// NodeStatus 一个节点上每个镜像的拉取状态
+type NodeStatus struct {
+	NodeName string
+	Images   []ImageStatus
+}
+
+type ImageStatus struct {
+	Image   string
+	State   string
+	Message string
+}
+
+//+kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch;create;update;delete
+
+// Puller 维护预拉取DaemonSet,实现了manager.Runnable,只在leader上运行
+type Puller struct {
+	client    client.Client
+	namespace string
+	cfg       conf.PrePullConfig
+	catalog   *catalog.Catalog
+}
+
+func NewPuller(client client.Client, namespace string, cfg conf.PrePullConfig, catalog *catalog.Catalog) *Puller {
+	return &Puller{client: client, namespace: namespace, cfg: cfg, catalog: catalog}
+}
+
+func (p *Puller) Start(ctx context.Context) error {
+	// 关闭预拉取时删除之前创建的DaemonSet
+	if !p.cfg.Enabled {
+		p.delete(ctx)
+		return nil
+	}
+
+	ticker := time.NewTicker(p.cfg.SyncInterval.Duration)
+	defer ticker.Stop()
+	for {
+		if err := p.Sync(ctx); err != nil {
+			klog.Errorf("sync image prepuller error:%v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync 使DaemonSet与镜像列表一致
+func (p *Puller) Sync(ctx context.Context) error {
+	images, err := p.images(ctx)
+	if err != nil {
+		return err
+	}
+	if len(images) == 0 {
+		p.delete(ctx)
+		return nil
+	}
+
+	desired := p.daemonSet(images)
+	current := &appsv1.DaemonSet{}
+	err = p.client.Get(ctx, client.ObjectKey{Name: Name, Namespace: p.namespace}, current)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		klog.Infof("create image prepuller, images:%v", images)
+		return p.client.Create(ctx, desired)
+	}
+	if current.Annotations[AnnotationHash] == desired.Annotations[AnnotationHash] {
+		return nil
+	}
+
+	klog.Infof("update image prepuller, images:%v", images)
+	current.Annotations = desired.Annotations
+	current.Spec.Template = desired.Spec.Template
+
+	return p.client.Update(ctx, current)
+}
+
+// Status 返回每个节点上镜像的拉取状态,image不为空时只返回该镜像
+func (p *Puller) Status(ctx context.Context, image string) ([]NodeStatus, error) {
+	if !p.cfg.Enabled {
+		return nil, nil
+	}
+	images, err := p.images(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if image != "" {
+		images = []string{image}
+		// 目录中的镜像在DaemonSet中是固定了digest的
+		if pinned, ok := p.pinned(ctx, image); ok {
+			images = []string{pinned}
+		}
+	}
+
+	pods := &v1.PodList{}
+	err = p.client.List(ctx, pods, client.InNamespace(p.namespace), client.MatchingLabels{LabelApp: Name})
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make([]NodeStatus, 0, len(pods.Items))
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName == "" || pod.DeletionTimestamp != nil {
+			continue
+		}
+		node := NodeStatus{NodeName: pod.Spec.NodeName}
+		for _, img := range images {
+			node.Images = append(node.Images, imageStatus(pod, img))
+		}
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].NodeName < nodes[j].NodeName
+	})
+
+	return nodes, nil
+}
+
+// imageStatus 根据镜像对应的容器状态判断拉取状态,旧版本的Pod中没有该镜像时为Pending
+func imageStatus(pod *v1.Pod, image string) ImageStatus {
+	name := ""
+	for _, c := range pod.Spec.Containers {
+		if c.Image == image {
+			name = c.Name
+			break
+		}
+	}
+	if name == "" {
+		return ImageStatus{Image: image, State: StatePending, Message: "waiting for prepuller rollout"}
+	}
+
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name != name {
+			continue
+		}
+		switch {
+		case cs.State.Terminated != nil || cs.State.Running != nil || cs.LastTerminationState.Terminated != nil:
+			// 容器已经启动过说明镜像已经在节点上,镜像中没有sleep导致容器反复重启时同样已经拉取
+			return ImageStatus{Image: image, State: StatePulled}
+		case cs.State.Waiting != nil:
+			switch cs.State.Waiting.Reason {
+			case "ErrImagePull", "ImagePullBackOff", "InvalidImageName", "ErrImageNeverPull":
+				return ImageStatus{Image: image, State: StateFailed, Message: cs.State.Waiting.Message}
+			}
+			return ImageStatus{Image: image, State: StatePulling, Message: cs.State.Waiting.Reason}
+		}
+	}
+
+	return ImageStatus{Image: image, State: StatePending}
+}
+
+// images 返回需要预拉取的镜像,目录中的镜像在前
+func (p *Puller) images(ctx context.Context) ([]string, error) {
+	images, err := p.catalog.Images(ctx)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(images))
+	for _, image := range images {
+		seen[image] = true
+	}
+	for _, image := range p.cfg.Images {
+		if !seen[image] {
+			seen[image] = true
+			images = append(images, image)
+		}
+	}
+
+	return images, nil
+}
+
+func (p *Puller) pinned(ctx context.Context, image string) (string, bool) {
+	img, ok, err := p.catalog.Find(ctx, image)
+	if err != nil || !ok {
+		return "", false
+	}
+
+	return p.catalog.Pin(ctx, img), true
+}
+
+func (p *Puller) daemonSet(images []string) *appsv1.DaemonSet {
+	labels := map[string]string{LabelApp: Name}
+	grace := int64(0)
+	requests := v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("1m"),
+		v1.ResourceMemory: resource.MustParse("8Mi"),
+	}
+
+	containers := make([]v1.Container, 0, len(images))
+	for i, image := range images {
+		containers = append(containers, v1.Container{
+			Name:            fmt.Sprintf("%s%d", containerPrefix, i),
+			Image:           image,
+			ImagePullPolicy: v1.PullIfNotPresent,
+			Command:         []string{"sh", "-c", "exec sleep 2147483647"},
+			Resources:       v1.ResourceRequirements{Requests: requests},
+		})
+	}
+	spec := v1.PodSpec{
+		Containers:                    containers,
+		NodeSelector:                  p.cfg.NodeSelector,
+		Tolerations:                   p.cfg.Tolerations,
+		TerminationGracePeriodSeconds: &grace,
+	}
+
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        Name,
+			Namespace:   p.namespace,
+			Labels:      labels,
+			Annotations: map[string]string{AnnotationHash: hash(spec)},
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec:       spec,
+			},
+		},
+	}
+}
+
+// hash Pod模板的哈希,镜像、节点选择器或容忍变化时DaemonSet需要更新
+func hash(spec v1.PodSpec) string {
+	data, _ := json.Marshal(spec)
+	h := sha256.Sum256(data)
+
+	return hex.EncodeToString(h[:])[:16]
+}
+
+func (p *Puller) delete(ctx context.Context) {
+	ds := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: Name, Namespace: p.namespace}}
+	err := p.client.Delete(ctx, ds, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	if err != nil && !errors.IsNotFound(err) {
+		klog.Errorf("delete image prepuller error:%v", err)
+	}
+}
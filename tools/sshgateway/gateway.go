@@ -0,0 +1,289 @@
+package sshgateway
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/conf"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/backend"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/workspace"
+	"golang.org/x/crypto/ssh"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+/*
+	SSH网关: 用户以工作空间名称作为用户名连接网关(ssh <工作空间>@<网关>),
+	网关根据工作空间PVC上的所有者查找该用户注册的公钥进行认证,认证通过后以网关的客户端密钥连接工作空间中的sshd,
+	双向转发会话、端口转发等通道和请求,VS Code Remote-SSH、JetBrains Gateway可以直接使用
+*/
+
+// permWorkspace 认证通过后记录在连接上的工作空间名称
+const permWorkspace = "workspace"
+
+// Gateway SSH网关,实现了manager.Runnable,所有副本都运行
+type Gateway struct {
+	client    client.Client
+	backend   backend.WorkspaceBackend
+	namespace string
+	cfg       conf.SSHConfig
+}
+
+func NewGateway(client client.Client, backend backend.WorkspaceBackend, namespace string, cfg conf.SSHConfig) *Gateway {
+	return &Gateway{
+		client:    client,
+		backend:   backend,
+		namespace: namespace,
+		cfg:       cfg,
+	}
+}
+
+func (g *Gateway) NeedLeaderElection() bool {
+	return false
+}
+
+// Start 加载密钥后开始监听,密钥加载失败(例如API Server暂时不可用)时不断重试,不影响manager中的其它组件
+func (g *Gateway) Start(ctx context.Context) error {
+	keys, err := LoadKeys(ctx, g.client, g.namespace)
+	for err != nil {
+		klog.Errorf("load ssh gateway keys error:%v, retry later", err)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Second * 10):
+		}
+		keys, err = LoadKeys(ctx, g.client, g.namespace)
+	}
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(meta ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			return g.authorize(ctx, meta, key)
+		},
+	}
+	config.AddHostKey(keys.Host)
+
+	ln, err := net.Listen("tcp", g.cfg.Address)
+	if err != nil {
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+	klog.Infof("ssh gateway listening on %s", g.cfg.Address)
+	// 限制同时处理的连接数
+	sem := make(chan struct{}, g.cfg.MaxConnections)
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			klog.Errorf("ssh gateway accept error:%v", err)
+			time.Sleep(time.Second)
+			continue
+		}
+		select {
+		case sem <- struct{}{}:
+		default:
+			klog.Warningf("too many ssh connections, reject remote:%s", conn.RemoteAddr())
+			conn.Close()
+			continue
+		}
+		go func() {
+			defer func() { <-sem }()
+			g.serve(ctx, conn, config, keys.Client)
+		}()
+	}
+}
+
+// authorize 工作空间存在且有所有者,并且公钥是所有者注册的公钥之一
+func (g *Gateway) authorize(ctx context.Context, meta ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+	name := meta.User()
+	pvc := &v1.PersistentVolumeClaim{}
+	if err := g.client.Get(ctx, client.ObjectKey{Namespace: g.namespace, Name: name}, pvc); err != nil {
+		return nil, fmt.Errorf("workspace %s not found", name)
+	}
+	owner := pvc.Labels[workspace.LabelOwner]
+	if owner == "" || pvc.DeletionTimestamp != nil || pvc.Labels[workspace.LabelState] == workspace.StateTrashed {
+		return nil, fmt.Errorf("workspace %s is not accessible", name)
+	}
+	lines, err := workspace.SSHKeys(ctx, g.client, g.namespace, owner)
+	if err != nil {
+		klog.Errorf("get ssh keys error:%v", err)
+		return nil, err
+	}
+	for _, line := range lines {
+		registered, _, _, _, err := ssh.ParseAuthorizedKey([]byte(line))
+		if err == nil && bytes.Equal(registered.Marshal(), key.Marshal()) {
+			return &ssh.Permissions{Extensions: map[string]string{permWorkspace: name}}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("public key %s is not registered by the owner of %s", ssh.FingerprintSHA256(key), name)
+}
+
+// serve 完成握手后连接工作空间中的sshd,并在两个连接之间转发
+func (g *Gateway) serve(ctx context.Context, conn net.Conn, config *ssh.ServerConfig, clientKey ssh.Signer) {
+	defer conn.Close()
+	// 握手完成前设置超时,避免不完成握手的连接一直占用
+	conn.SetDeadline(time.Now().Add(g.cfg.HandshakeTimeout.Duration))
+	sconn, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		klog.V(2).Infof("ssh handshake failed, remote:%s, err:%v", conn.RemoteAddr(), err)
+		return
+	}
+	defer sconn.Close()
+	conn.SetDeadline(time.Time{})
+	name := sconn.Permissions.Extensions[permWorkspace]
+
+	uconn, uchans, ureqs, err := g.dial(ctx, name, clientKey)
+	if err != nil {
+		klog.Errorf("connect workspace %s error:%v", name, err)
+		// 拒绝用户打开的通道,客户端会显示原因
+		go ssh.DiscardRequests(reqs)
+		for nc := range chans {
+			nc.Reject(ssh.ConnectionFailed, fmt.Sprintf("workspace %s is not reachable: %v", name, err))
+		}
+		return
+	}
+	defer uconn.Close()
+	klog.Infof("ssh session opened, workspace:%s, remote:%s", name, sconn.RemoteAddr())
+
+	go forwardRequests(reqs, uconn)
+	go forwardRequests(ureqs, sconn)
+	// 工作空间发起的通道,例如远程端口转发
+	go func() {
+		forwardChannels(uchans, sconn)
+		sconn.Close()
+	}()
+	forwardChannels(chans, uconn)
+	klog.Infof("ssh session closed, workspace:%s, remote:%s", name, sconn.RemoteAddr())
+}
+
+// dial 连接运行中的工作空间中的sshd
+func (g *Gateway) dial(ctx context.Context, name string, clientKey ssh.Signer) (ssh.Conn, <-chan ssh.NewChannel, <-chan *ssh.Request, error) {
+	pod, err := g.backend.Get(ctx, g.namespace, name)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("workspace is not running")
+	}
+	if !workspace.IsPodReady(pod) || pod.Status.PodIP == "" {
+		return nil, nil, nil, fmt.Errorf("workspace is not ready")
+	}
+	addr := net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(int(g.cfg.Port)))
+	conn, err := net.DialTimeout("tcp", addr, 10*time.Second)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// 工作空间的主机密钥随Pod重建而变化,网关到Pod的连接在集群网络中,不校验主机密钥
+	uconn, uchans, ureqs, err := ssh.NewClientConn(conn, addr, &ssh.ClientConfig{
+		User:            g.cfg.User,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(clientKey)},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         10 * time.Second,
+	})
+	if err != nil {
+		conn.Close()
+		return nil, nil, nil, err
+	}
+
+	return uconn, uchans, ureqs, nil
+}
+
+// forwardRequests 将连接级别的请求(例如tcpip-forward)转发到另一个连接
+func forwardRequests(in <-chan *ssh.Request, out ssh.Conn) {
+	for req := range in {
+		ok, payload, err := out.SendRequest(req.Type, req.WantReply, req.Payload)
+		if err != nil {
+			ok = false
+		}
+		if req.WantReply {
+			req.Reply(ok, payload)
+		}
+	}
+}
+
+// forwardChannels 在另一个连接上打开相同的通道并双向转发
+func forwardChannels(in <-chan ssh.NewChannel, out ssh.Conn) {
+	for nc := range in {
+		go func(nc ssh.NewChannel) {
+			och, oreqs, err := out.OpenChannel(nc.ChannelType(), nc.ExtraData())
+			if err != nil {
+				if oerr, ok := err.(*ssh.OpenChannelError); ok {
+					nc.Reject(oerr.Reason, oerr.Message)
+				} else {
+					nc.Reject(ssh.ConnectionFailed, err.Error())
+				}
+				return
+			}
+			ich, ireqs, err := nc.Accept()
+			if err != nil {
+				och.Close()
+				return
+			}
+			pipe(ich, ireqs, och, oreqs)
+		}(nc)
+	}
+}
+
+// pipe 双向转发两个通道的数据和请求,一端关闭且数据和请求(例如exit-status)转发完成后关闭另一端
+func pipe(a ssh.Channel, aReqs <-chan *ssh.Request, b ssh.Channel, bReqs <-chan *ssh.Request) {
+	var toA, toB sync.WaitGroup
+	toA.Add(3)
+	toB.Add(3)
+	go func() {
+		defer toA.Done()
+		io.Copy(a, b)
+		a.CloseWrite()
+	}()
+	go func() {
+		defer toB.Done()
+		io.Copy(b, a)
+		b.CloseWrite()
+	}()
+	go func() {
+		defer toA.Done()
+		io.Copy(a.Stderr(), b.Stderr())
+	}()
+	go func() {
+		defer toB.Done()
+		io.Copy(b.Stderr(), a.Stderr())
+	}()
+	go func() {
+		defer toA.Done()
+		forwardChannelRequests(bReqs, a)
+	}()
+	go func() {
+		defer toB.Done()
+		forwardChannelRequests(aReqs, b)
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		toA.Wait()
+		a.Close()
+		close(done)
+	}()
+	toB.Wait()
+	b.Close()
+	<-done
+}
+
+func forwardChannelRequests(in <-chan *ssh.Request, out ssh.Channel) {
+	for req := range in {
+		ok, err := out.SendRequest(req.Type, req.WantReply, req.Payload)
+		if err != nil {
+			ok = false
+		}
+		if req.WantReply {
+			req.Reply(ok, nil)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package sshgateway
+
+import (
+	"context"
+	"crypto/sha256"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/conf"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/workspace"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/crypto/ssh"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const namespace = "cloud-ide"
+
+// connMeta 只实现authorize用到的User
+type connMeta struct {
+	ssh.ConnMetadata
+	user string
+}
+
+func (m connMeta) User() string {
+	return m.user
+}
+
+// userKey 由名称生成固定的用户公钥
+func userKey(name string) ssh.PublicKey {
+	seed := sha256.Sum256([]byte(name))
+	s, err := signer(seed[:])
+	Expect(err).NotTo(HaveOccurred())
+	return s.PublicKey()
+}
+
+func workspacePVC(name string, labels map[string]string) *v1.PersistentVolumeClaim {
+	return &v1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels}}
+}
+
+func keysSecret(owner string, lines string) *v1.Secret {
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workspace.SSHKeysSecretName(owner),
+			Namespace: namespace,
+			Labels:    map[string]string{workspace.LabelKind: workspace.KindSSHKeys, workspace.LabelOwner: owner},
+		},
+		Data: map[string][]byte{workspace.SSHKeysDataKey: []byte(lines)},
+	}
+}
+
+var _ = Describe("Gateway authorize", func() {
+	var g *Gateway
+
+	BeforeEach(func() {
+		c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+			workspacePVC("ws-alice", map[string]string{workspace.LabelOwner: "alice"}),
+			workspacePVC("ws-bob", map[string]string{workspace.LabelOwner: "bob"}),
+			workspacePVC("ws-carol", map[string]string{workspace.LabelOwner: "carol"}),
+			workspacePVC("ws-legacy", nil),
+			workspacePVC("ws-trashed", map[string]string{workspace.LabelOwner: "alice", workspace.LabelState: workspace.StateTrashed}),
+			keysSecret("alice", "\n"+AuthorizedKey(userKey("alice"))+" alice@laptop\n\n"+AuthorizedKey(userKey("alice-desktop"))+"\n"),
+			keysSecret("bob", AuthorizedKey(userKey("bob"))),
+		).Build()
+		g = NewGateway(c, nil, namespace, conf.SSHConfig{})
+	})
+
+	DescribeTable("only keys registered by the workspace owner are accepted",
+		func(user, key string, allowed bool) {
+			perms, err := g.authorize(context.Background(), connMeta{user: user}, userKey(key))
+			if !allowed {
+				Expect(err).To(HaveOccurred())
+				Expect(perms).To(BeNil())
+				return
+			}
+			Expect(err).NotTo(HaveOccurred())
+			Expect(perms.Extensions).To(HaveKeyWithValue(permWorkspace, user))
+		},
+		Entry("owner key", "ws-alice", "alice", true),
+		Entry("another key of the owner", "ws-alice", "alice-desktop", true),
+		Entry("key of another owner", "ws-alice", "bob", false),
+		Entry("owner key on another owner's workspace", "ws-bob", "alice", false),
+		Entry("owner without registered keys", "ws-carol", "alice", false),
+		Entry("workspace without owner", "ws-legacy", "alice", false),
+		Entry("trashed workspace", "ws-trashed", "alice", false),
+		Entry("unknown workspace", "ws-unknown", "alice", false),
+	)
+})
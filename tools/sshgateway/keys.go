@@ -0,0 +1,87 @@
+package sshgateway
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+
+	"golang.org/x/crypto/ssh"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// SecretName 保存网关密钥的Secret,第一次启动时生成,所有副本共享
+	SecretName = "cloud-ide-ssh-gateway"
+	// 网关的主机密钥和连接工作空间使用的客户端密钥,ed25519种子
+	hostKeyData   = "host-key"
+	clientKeyData = "client-key"
+)
+
+// Keys 网关的密钥
+type Keys struct {
+	// 用户连接网关时验证的主机密钥
+	Host ssh.Signer
+	// 网关连接工作空间中sshd的密钥,公钥写入工作空间的authorized_keys
+	Client ssh.Signer
+}
+
+// LoadKeys 读取网关的密钥,不存在时生成并保存
+func LoadKeys(ctx context.Context, c client.Client, namespace string) (*Keys, error) {
+	secret := &v1.Secret{}
+	err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: SecretName}, secret)
+	if errors.IsNotFound(err) {
+		secret, err = createKeys(ctx, c, namespace)
+		if errors.IsAlreadyExists(err) {
+			// 其它副本已经生成
+			err = c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: SecretName}, secret)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	host, err := signer(secret.Data[hostKeyData])
+	if err != nil {
+		return nil, err
+	}
+	cli, err := signer(secret.Data[clientKeyData])
+	if err != nil {
+		return nil, err
+	}
+
+	return &Keys{Host: host, Client: cli}, nil
+}
+
+func createKeys(ctx context.Context, c client.Client, namespace string) (*v1.Secret, error) {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: SecretName, Namespace: namespace},
+		Data:       make(map[string][]byte),
+	}
+	for _, key := range []string{hostKeyData, clientKeyData} {
+		seed := make([]byte, ed25519.SeedSize)
+		if _, err := rand.Read(seed); err != nil {
+			return nil, err
+		}
+		secret.Data[key] = seed
+	}
+
+	return secret, c.Create(ctx, secret)
+}
+
+func signer(seed []byte) (ssh.Signer, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("invalid ssh gateway key in secret %s", SecretName)
+	}
+
+	return ssh.NewSignerFromKey(ed25519.NewKeyFromSeed(seed))
+}
+
+// AuthorizedKey 返回公钥的authorized_keys格式,不包含换行
+func AuthorizedKey(key ssh.PublicKey) string {
+	line := ssh.MarshalAuthorizedKey(key)
+	return string(line[:len(line)-1])
+}
@@ -0,0 +1,14 @@
+package sshgateway
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestSSHGateway(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "SSH Gateway Suite")
+}
@@ -0,0 +1,272 @@
+package warmpool
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/conf"
+	"github.com/mangohow/cloud-ide-k8s-controller/tools/workspace"
+	"github.com/prometheus/client_golang/prometheus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+/*
+	预热池: 为常用镜像保持N个已经Running的Pod,这些Pod所在的节点已经拉取了镜像,并且预留了工作空间需要的资源。
+	Kubernetes无法为运行中的Pod挂载存储卷,因此工作空间启动时不能直接使用预热Pod,
+	而是删除一个预热Pod,并让工作空间的Pod优先调度到该节点上,省去等待调度资源和拉取镜像的时间,
+	没有可用的预热Pod时按正常流程创建,之后补充预热池
+*/
+
+const (
+	// KindWarm 预热Pod的kind标签值,与工作空间的Pod区分,PodReconciler和垃圾回收不会处理预热Pod
+	KindWarm = "cloud-ide-warm"
+	// LabelImage 预热Pod镜像的哈希,镜像名称不能直接作为标签值
+	LabelImage = "cloud-ide.mangohow.com/warm-image"
+	// AnnotationImage 预热Pod的镜像
+	AnnotationImage = "cloud-ide.mangohow.com/warm-image"
+)
+
+var (
+	claimTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "cloud_ide_warmpool_claims_total",
+		Help: "Number of workspace starts that tried to claim a warm pod, by result (hit or miss).",
+	}, []string{"image", "result"})
+	readyPods = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "cloud_ide_warmpool_ready_pods",
+		Help: "Number of ready warm pods per image.",
+	}, []string{"image"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(claimTotal, readyPods)
+}
+
+// Pool 预热池,实现了manager.Runnable,只在leader上补充预热Pod
+type Pool struct {
+	client    client.Client
+	namespace string
+	cfg       conf.WarmPoolConfig
+	pools     map[string]conf.WarmPool
+	refill    chan struct{}
+}
+
+func NewPool(client client.Client, namespace string, cfg conf.WarmPoolConfig) (*Pool, error) {
+	pools := make(map[string]conf.WarmPool, len(cfg.Pools))
+	for _, p := range cfg.Pools {
+		if p.Image == "" || p.Size < 0 {
+			return nil, fmt.Errorf("invalid warm pool, image:%q, size:%d", p.Image, p.Size)
+		}
+		if _, err := resource.ParseQuantity(p.CPU); err != nil {
+			return nil, fmt.Errorf("invalid warm pool cpu %q: %v", p.CPU, err)
+		}
+		if _, err := resource.ParseQuantity(p.Memory); err != nil {
+			return nil, fmt.Errorf("invalid warm pool memory %q: %v", p.Memory, err)
+		}
+		pools[p.Image] = p
+	}
+
+	return &Pool{
+		client:    client,
+		namespace: namespace,
+		cfg:       cfg,
+		pools:     pools,
+		refill:    make(chan struct{}, 1),
+	}, nil
+}
+
+func (p *Pool) Start(ctx context.Context) error {
+	ticker := time.NewTicker(p.cfg.RefillInterval.Duration)
+	defer ticker.Stop()
+	p.Refill(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case <-p.refill:
+		}
+		p.Refill(ctx)
+	}
+}
+
+// Claim 占用一个image的预热Pod,返回其所在的节点,没有可用的预热Pod时返回false
+func (p *Pool) Claim(ctx context.Context, image string) (string, bool) {
+	if p == nil {
+		return "", false
+	}
+	if _, ok := p.pools[image]; !ok {
+		return "", false
+	}
+	defer p.triggerRefill()
+
+	pods, err := p.list(ctx, image)
+	if err != nil {
+		klog.Errorf("list warm pods error:%v", err)
+		claimTotal.WithLabelValues(image, "miss").Inc()
+		return "", false
+	}
+	for i := range pods {
+		pod := &pods[i]
+		if pod.DeletionTimestamp != nil || !workspace.IsPodReady(pod) {
+			continue
+		}
+		// 通过UID前置条件保证同一个预热Pod只会被一个工作空间占用
+		err := p.client.Delete(ctx, pod, client.Preconditions{UID: &pod.UID}, client.GracePeriodSeconds(0))
+		if err != nil {
+			continue
+		}
+		klog.Infof("claimed warm pod %s on node %s", pod.Name, pod.Spec.NodeName)
+		claimTotal.WithLabelValues(image, "hit").Inc()
+		return pod.Spec.NodeName, true
+	}
+	claimTotal.WithLabelValues(image, "miss").Inc()
+
+	return "", false
+}
+
+// Refill 使每个镜像的预热Pod数量与配置一致,并删除已经不在配置中的预热Pod
+func (p *Pool) Refill(ctx context.Context) {
+	all := &v1.PodList{}
+	if err := p.client.List(ctx, all, client.InNamespace(p.namespace), client.MatchingLabels{workspace.LabelKind: KindWarm}); err != nil {
+		klog.Errorf("list warm pods error:%v", err)
+		return
+	}
+	byImage := make(map[string][]*v1.Pod)
+	for i := range all.Items {
+		pod := &all.Items[i]
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		image := pod.Annotations[AnnotationImage]
+		if _, ok := p.pools[image]; !ok {
+			p.delete(ctx, pod)
+			continue
+		}
+		byImage[image] = append(byImage[image], pod)
+	}
+
+	for image, pool := range p.pools {
+		pods := byImage[image]
+		ready := 0
+		for _, pod := range pods {
+			if workspace.IsPodReady(pod) {
+				ready++
+			}
+		}
+		readyPods.WithLabelValues(image).Set(float64(ready))
+
+		// 失败的预热Pod无法恢复,删除后重新创建
+		for _, pod := range pods {
+			if pod.Status.Phase == v1.PodFailed || pod.Status.Phase == v1.PodSucceeded {
+				p.delete(ctx, pod)
+			}
+		}
+		for i := len(pods); i < pool.Size; i++ {
+			if err := p.client.Create(ctx, p.newPod(pool)); err != nil {
+				klog.Errorf("create warm pod error:%v, image:%s", err, image)
+				break
+			}
+		}
+		// 预热池缩小时,优先删除还没有就绪的Pod
+		if len(pods) > pool.Size {
+			sort.Slice(pods, func(i, j int) bool {
+				return !workspace.IsPodReady(pods[i]) && workspace.IsPodReady(pods[j])
+			})
+			for _, pod := range pods[:len(pods)-pool.Size] {
+				p.delete(ctx, pod)
+			}
+		}
+	}
+}
+
+// PreferNode 工作空间Pod优先调度到预热Pod释放的节点上,节点资源被占用时仍然可以调度到其它节点,
+// Pod已有的调度约束保持不变
+func PreferNode(pod *v1.Pod, nodeName string) {
+	if pod.Spec.Affinity == nil {
+		pod.Spec.Affinity = &v1.Affinity{}
+	}
+	if pod.Spec.Affinity.NodeAffinity == nil {
+		pod.Spec.Affinity.NodeAffinity = &v1.NodeAffinity{}
+	}
+	na := pod.Spec.Affinity.NodeAffinity
+	na.PreferredDuringSchedulingIgnoredDuringExecution = append(na.PreferredDuringSchedulingIgnoredDuringExecution, v1.PreferredSchedulingTerm{
+		Weight: 100,
+		Preference: v1.NodeSelectorTerm{
+			MatchFields: []v1.NodeSelectorRequirement{
+				{
+					Key:      "metadata.name",
+					Operator: v1.NodeSelectorOpIn,
+					Values:   []string{nodeName},
+				},
+			},
+		},
+	})
+}
+
+func (p *Pool) list(ctx context.Context, image string) ([]v1.Pod, error) {
+	pods := &v1.PodList{}
+	err := p.client.List(ctx, pods, client.InNamespace(p.namespace),
+		client.MatchingLabels{workspace.LabelKind: KindWarm, LabelImage: imageHash(image)})
+
+	return pods.Items, err
+}
+
+func (p *Pool) newPod(pool conf.WarmPool) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "warm-",
+			Namespace:    p.namespace,
+			Labels: map[string]string{
+				workspace.LabelKind: KindWarm,
+				LabelImage:          imageHash(pool.Image),
+			},
+			Annotations: map[string]string{
+				AnnotationImage: pool.Image,
+			},
+		},
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Name:            "warm",
+					Image:           pool.Image,
+					ImagePullPolicy: v1.PullIfNotPresent,
+					Resources: v1.ResourceRequirements{
+						Requests: v1.ResourceList{
+							v1.ResourceCPU:    resource.MustParse(pool.CPU),
+							v1.ResourceMemory: resource.MustParse(pool.Memory),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (p *Pool) delete(ctx context.Context, pod *v1.Pod) {
+	if err := p.client.Delete(ctx, pod, client.GracePeriodSeconds(0)); err != nil && !errors.IsNotFound(err) {
+		klog.Errorf("delete warm pod error:%v, pod:%s", err, pod.Name)
+	}
+}
+
+func (p *Pool) triggerRefill() {
+	select {
+	case p.refill <- struct{}{}:
+	default:
+	}
+}
+
+func imageHash(image string) string {
+	sum := sha256.Sum256([]byte(image))
+
+	return hex.EncodeToString(sum[:])[:16]
+}
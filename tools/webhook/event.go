@@ -0,0 +1,38 @@
+package webhook
+
+import "time"
+
+// 工作空间生命周期事件类型
+const (
+	EventWorkspaceRunning   = "workspace.running"
+	EventWorkspaceFailed    = "workspace.failed"
+	EventWorkspaceRestarted = "workspace.restarted"
+	EventWorkspaceStopped   = "workspace.stopped"
+	// 工作空间所在节点即将维护,通知期结束后迁移到其它节点
+	EventWorkspaceDraining = "workspace.draining"
+)
+
+// Event 发送给订阅方的事件
+type Event struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	Workspace string `json:"workspace"`
+	Namespace string `json:"namespace"`
+	NodeName  string `json:"nodeName,omitempty"`
+	Phase     string `json:"phase,omitempty"`
+	// 状态变化的原因,例如Evicted、OOMKilled、NodeLost、StopRequested
+	Reason string `json:"reason,omitempty"`
+	// 是否由调用方主动触发(StopSpace等),为false表示工作空间自己停止
+	Expected  bool      `json:"expected"`
+	Message   string    `json:"message,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// delivery 一次投递任务,一个事件对每个订阅各有一个投递任务
+type delivery struct {
+	ID           string    `json:"id"`
+	Subscription string    `json:"subscription"`
+	Event        Event     `json:"event"`
+	Attempts     int       `json:"attempts"`
+	NextAttempt  time.Time `json:"nextAttempt"`
+}
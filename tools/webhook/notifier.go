@@ -0,0 +1,208 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/conf"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// 请求头,订阅方使用Secret对 "<timestamp>.<body>" 计算HMAC-SHA256并与签名比较
+const (
+	HeaderEvent     = "X-CloudIde-Event"
+	HeaderDelivery  = "X-CloudIde-Delivery"
+	HeaderTimestamp = "X-CloudIde-Timestamp"
+	HeaderSignature = "X-CloudIde-Signature"
+)
+
+// Notifier 将工作空间生命周期事件通过webhook通知给订阅方,待投递的事件保存在ConfigMap中,
+// 投递失败时按指数退避重试,实现了manager.Runnable,由manager在leader上启动
+type Notifier struct {
+	cfg    conf.WebhookConfig
+	subs   map[string]conf.WebhookSubscription
+	queue  *queue
+	client *http.Client
+	wakeup chan struct{}
+}
+
+func NewNotifier(c client.Client, reader client.Reader, namespace string, cfg conf.WebhookConfig) (*Notifier, error) {
+	q := newQueue(c, reader, namespace, cfg.QueueConfigMap, cfg.MaxPending)
+	subs := make(map[string]conf.WebhookSubscription, len(cfg.Subscriptions))
+	for _, sub := range cfg.Subscriptions {
+		if sub.Name == "" || sub.URL == "" {
+			return nil, fmt.Errorf("webhook subscription must have name and url")
+		}
+		subs[sub.Name] = sub
+	}
+
+	return &Notifier{
+		cfg:    cfg,
+		subs:   subs,
+		queue:  q,
+		client: &http.Client{Timeout: cfg.Timeout.Duration},
+		wakeup: make(chan struct{}, 1),
+	}, nil
+}
+
+// Notify 将事件加入内存中的投递队列后立即返回,队列由后台批量写回ConfigMap
+func (n *Notifier) Notify(ev Event) {
+	if len(n.subs) == 0 {
+		return
+	}
+	if ev.ID == "" {
+		ev.ID = newID()
+	}
+	if ev.Timestamp.IsZero() {
+		ev.Timestamp = time.Now()
+	}
+	for _, sub := range n.subs {
+		if !subscribed(sub, ev.Type) {
+			continue
+		}
+		n.queue.put(&delivery{ID: newID(), Subscription: sub.Name, Event: ev, NextAttempt: time.Now()})
+	}
+
+	select {
+	case n.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+// Start 恢复未完成的投递后投递队列中的事件,直到ctx结束
+func (n *Notifier) Start(ctx context.Context) error {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for err := n.queue.load(ctx); err != nil; err = n.queue.load(ctx) {
+		klog.Errorf("load webhook queue error:%v, retry later", err)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Second * 5):
+		}
+	}
+	go n.queue.run(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case <-n.wakeup:
+		}
+		n.deliverDue(ctx)
+	}
+}
+
+func (n *Notifier) deliverDue(ctx context.Context) {
+	now := time.Now()
+	for _, d := range n.queue.list() {
+		if ctx.Err() != nil {
+			return
+		}
+		if d.NextAttempt.After(now) {
+			continue
+		}
+		sub, ok := n.subs[d.Subscription]
+		if !ok {
+			// 订阅已经从配置中删除
+			n.queue.remove(d.ID)
+			continue
+		}
+		err := n.send(ctx, sub, &d)
+		if err == nil {
+			n.queue.remove(d.ID)
+			continue
+		}
+
+		d.Attempts++
+		if d.Attempts > n.cfg.MaxRetries {
+			klog.Errorf("webhook delivery dropped after %d attempts, subscription:%s, event:%s, err:%v", d.Attempts, sub.Name, d.Event.Type, err)
+			n.queue.remove(d.ID)
+			continue
+		}
+		d.NextAttempt = now.Add(n.backoff(d.Attempts))
+		klog.Infof("webhook delivery failed, retry at %s, subscription:%s, err:%v", d.NextAttempt.Format(time.RFC3339), sub.Name, err)
+		n.queue.update(&d)
+	}
+}
+
+func (n *Notifier) send(ctx context.Context, sub conf.WebhookSubscription, d *delivery) error {
+	body, err := json.Marshal(d.Event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, d.Event.Type)
+	req.Header.Set(HeaderDelivery, d.ID)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, "sha256="+Sign(sub.Secret, timestamp, body))
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// backoff 第attempts次失败后的等待时间
+func (n *Notifier) backoff(attempts int) time.Duration {
+	backoff := n.cfg.InitialBackoff.Duration
+	for i := 1; i < attempts && backoff < n.cfg.MaxBackoff.Duration; i++ {
+		backoff *= 2
+	}
+	if backoff > n.cfg.MaxBackoff.Duration {
+		backoff = n.cfg.MaxBackoff.Duration
+	}
+
+	return backoff
+}
+
+// Sign 计算 "<timestamp>.<body>" 的HMAC-SHA256签名
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func subscribed(sub conf.WebhookSubscription, eventType string) bool {
+	if len(sub.Events) == 0 {
+		return true
+	}
+	for _, e := range sub.Events {
+		if e == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,142 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	"github.com/mangohow/cloud-ide-k8s-controller/conf"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Sign", func() {
+	DescribeTable("HMAC-SHA256 over <timestamp>.<body>",
+		func(secret, timestamp, body, expected string) {
+			Expect(Sign(secret, timestamp, []byte(body))).To(Equal(expected))
+		},
+		Entry("event body", "change-me", "1700000000", `{"id":"1"}`,
+			"36b765ceed6175ea703ea0e5051f2b81c392b93a489747de783f277ea58d9fac"),
+		Entry("another secret", "s3cr3t", "1700000000", `{"type":"workspace.stopped"}`,
+			"c63daf545215188db590344b8ab736bf256d82b1411f7dc868008379b5d7b4d9"),
+		Entry("empty secret and body", "", "0", "",
+			"b849d5a581847b281957065739df36df2463d1977ea8d6e1e4e6cf33fadc68c3"),
+	)
+})
+
+var _ = Describe("Notifier", func() {
+	newNotifier := func(cfg conf.WebhookConfig) *Notifier {
+		return &Notifier{
+			cfg:    cfg,
+			subs:   make(map[string]conf.WebhookSubscription),
+			queue:  newQueue(nil, nil, "cloud-ide", "queue", 0),
+			client: &http.Client{Timeout: time.Second},
+			wakeup: make(chan struct{}, 1),
+		}
+	}
+
+	DescribeTable("send",
+		func(status int, succeed bool) {
+			var req *http.Request
+			var body []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				req = r
+				body, _ = io.ReadAll(r.Body)
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+
+			n := newNotifier(conf.WebhookConfig{})
+			sub := conf.WebhookSubscription{Name: "backend", URL: server.URL, Secret: "change-me"}
+			d := &delivery{ID: "d-1", Subscription: sub.Name, Event: Event{ID: "e-1", Type: EventWorkspaceStopped, Workspace: "ws-1"}}
+			err := n.send(context.Background(), sub, d)
+			if succeed {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(HaveOccurred())
+			}
+
+			// 订阅方按文档校验签名
+			Expect(req.Header.Get(HeaderEvent)).To(Equal(EventWorkspaceStopped))
+			Expect(req.Header.Get(HeaderDelivery)).To(Equal("d-1"))
+			Expect(req.Header.Get("Content-Type")).To(Equal("application/json"))
+			timestamp := req.Header.Get(HeaderTimestamp)
+			Expect(timestamp).NotTo(BeEmpty())
+			mac := hmac.New(sha256.New, []byte("change-me"))
+			mac.Write([]byte(timestamp + "." + string(body)))
+			Expect(req.Header.Get(HeaderSignature)).To(Equal("sha256=" + hex.EncodeToString(mac.Sum(nil))))
+
+			ev := Event{}
+			Expect(json.Unmarshal(body, &ev)).To(Succeed())
+			Expect(ev).To(Equal(d.Event))
+		},
+		Entry("2xx succeeds", http.StatusOK, true),
+		Entry("204 succeeds", http.StatusNoContent, true),
+		Entry("4xx fails", http.StatusUnauthorized, false),
+		Entry("5xx fails", http.StatusInternalServerError, false),
+	)
+
+	DescribeTable("backoff doubles up to MaxBackoff",
+		func(attempts int, expected time.Duration) {
+			n := newNotifier(conf.WebhookConfig{
+				InitialBackoff: metav1.Duration{Duration: time.Second},
+				MaxBackoff:     metav1.Duration{Duration: 10 * time.Second},
+			})
+			Expect(n.backoff(attempts)).To(Equal(expected))
+		},
+		Entry("first failure", 1, time.Second),
+		Entry("second failure", 2, 2*time.Second),
+		Entry("fourth failure", 4, 8*time.Second),
+		Entry("capped", 5, 10*time.Second),
+		Entry("many failures", 30, 10*time.Second),
+	)
+
+	It("drops a delivery after MaxRetries failed retries", func() {
+		var hits int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&hits, 1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		// 退避时间为0,每次deliverDue都会重试
+		n := newNotifier(conf.WebhookConfig{MaxRetries: 2})
+		n.subs["backend"] = conf.WebhookSubscription{Name: "backend", URL: server.URL}
+		n.Notify(Event{Type: EventWorkspaceFailed, Workspace: "ws-1"})
+		Expect(n.queue.list()).To(HaveLen(1))
+
+		n.deliverDue(context.Background())
+		n.deliverDue(context.Background())
+		Expect(n.queue.list()).To(HaveLen(1))
+		Expect(n.queue.list()[0].Attempts).To(Equal(2))
+
+		n.deliverDue(context.Background())
+		Expect(n.queue.list()).To(BeEmpty())
+		Expect(atomic.LoadInt32(&hits)).To(Equal(int32(3)))
+	})
+
+	It("removes a delivery once it succeeds and only for subscribed events", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		n := newNotifier(conf.WebhookConfig{MaxRetries: 2})
+		n.subs["backend"] = conf.WebhookSubscription{Name: "backend", URL: server.URL, Events: []string{EventWorkspaceStopped}}
+		n.Notify(Event{Type: EventWorkspaceRunning, Workspace: "ws-1"})
+		Expect(n.queue.list()).To(BeEmpty())
+
+		n.Notify(Event{Type: EventWorkspaceStopped, Workspace: "ws-1"})
+		Expect(n.queue.list()).To(HaveLen(1))
+		n.deliverDue(context.Background())
+		Expect(n.queue.list()).To(BeEmpty())
+	})
+})
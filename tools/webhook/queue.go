@@ -0,0 +1,198 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=create;update
+
+// flushInterval 两次写回ConfigMap之间的最短间隔,间隔内的修改合并为一次写入
+const flushInterval = time.Second
+
+// queue 投递队列,每个投递任务以json保存在ConfigMap的一个key中,控制器重启或切换leader后
+// 新的leader从ConfigMap中恢复未完成的投递。队列只由leader写入,内存中的副本是权威数据,
+// 修改只发生在内存中,由run在后台批量整体写回。任务数超过max时丢弃最早的事件,避免ConfigMap超过大小限制
+type queue struct {
+	sync.Mutex
+	client    client.Client
+	reader    client.Reader
+	namespace string
+	name      string
+	max       int
+	items     map[string]*delivery
+	// 有还没有写回ConfigMap的修改
+	dirty bool
+	// 从ConfigMap加载之前不写回,加载时合并
+	loaded bool
+}
+
+func newQueue(c client.Client, reader client.Reader, namespace, name string, max int) *queue {
+	return &queue{
+		client:    c,
+		reader:    reader,
+		namespace: namespace,
+		name:      name,
+		max:       max,
+		items:     make(map[string]*delivery),
+	}
+}
+
+// load 从ConfigMap中恢复投递任务,与加载前加入的任务合并,由run写回
+func (q *queue) load(ctx context.Context) error {
+	cm := &v1.ConfigMap{}
+	err := q.reader.Get(ctx, client.ObjectKey{Namespace: q.namespace, Name: q.name}, cm)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	q.Lock()
+	defer q.Unlock()
+	for id, data := range cm.Data {
+		if _, ok := q.items[id]; ok {
+			continue
+		}
+		d := &delivery{}
+		if err := json.Unmarshal([]byte(data), d); err != nil {
+			// 损坏的任务直接丢弃,避免阻塞整个队列
+			continue
+		}
+		q.items[d.ID] = d
+	}
+	q.evict()
+	q.loaded = true
+	q.dirty = true
+
+	return nil
+}
+
+// put 添加投递任务
+func (q *queue) put(d *delivery) {
+	q.Lock()
+	defer q.Unlock()
+	q.items[d.ID] = d
+	q.evict()
+	q.dirty = true
+}
+
+// update 更新投递任务,任务已经被删除时忽略
+func (q *queue) update(d *delivery) {
+	q.Lock()
+	defer q.Unlock()
+	if _, ok := q.items[d.ID]; !ok {
+		return
+	}
+	q.items[d.ID] = d
+	q.dirty = true
+}
+
+func (q *queue) remove(id string) {
+	q.Lock()
+	defer q.Unlock()
+	delete(q.items, id)
+	q.dirty = true
+}
+
+// list 返回所有投递任务的副本
+func (q *queue) list() []delivery {
+	q.Lock()
+	defer q.Unlock()
+	res := make([]delivery, 0, len(q.items))
+	for _, d := range q.items {
+		res = append(res, *d)
+	}
+
+	return res
+}
+
+// evict 任务数超过上限时丢弃最早的事件,调用时需要持有锁
+func (q *queue) evict() {
+	for q.max > 0 && len(q.items) > q.max {
+		var oldest *delivery
+		for _, d := range q.items {
+			if oldest == nil || d.Event.Timestamp.Before(oldest.Event.Timestamp) {
+				oldest = d
+			}
+		}
+		delete(q.items, oldest.ID)
+		klog.Errorf("webhook queue is full, dropped delivery, subscription:%s, event:%s, workspace:%s",
+			oldest.Subscription, oldest.Event.Type, oldest.Event.Workspace)
+	}
+}
+
+// run 定期将修改写回ConfigMap,直到ctx结束,结束前再写回一次
+func (q *queue) run(ctx context.Context) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+			err := q.flush(flushCtx)
+			cancel()
+			if err != nil {
+				klog.Errorf("save webhook queue error:%v", err)
+			}
+			return
+		case <-ticker.C:
+		}
+		if err := q.flush(ctx); err != nil {
+			klog.Errorf("save webhook queue error:%v", err)
+		}
+	}
+}
+
+// flush 有修改时将内存中的任务整体写回ConfigMap,写入失败时下次重试
+func (q *queue) flush(ctx context.Context) error {
+	q.Lock()
+	if !q.loaded || !q.dirty {
+		q.Unlock()
+		return nil
+	}
+	data := make(map[string]string, len(q.items))
+	for id, d := range q.items {
+		b, err := json.Marshal(d)
+		if err != nil {
+			q.Unlock()
+			return err
+		}
+		data[id] = string(b)
+	}
+	q.dirty = false
+	q.Unlock()
+
+	if err := q.save(ctx, data); err != nil {
+		q.Lock()
+		q.dirty = true
+		q.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+func (q *queue) save(ctx context.Context, data map[string]string) error {
+	cm := &v1.ConfigMap{}
+	err := q.reader.Get(ctx, client.ObjectKey{Namespace: q.namespace, Name: q.name}, cm)
+	if apierrors.IsNotFound(err) {
+		cm = &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: q.namespace, Name: q.name},
+			Data:       data,
+		}
+		return q.client.Create(ctx, cm)
+	}
+	if err != nil {
+		return err
+	}
+	cm.Data = data
+
+	return q.client.Update(ctx, cm)
+}
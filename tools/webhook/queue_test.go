@@ -0,0 +1,144 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	namespace = "cloud-ide"
+	queueName = "cloud-ide-webhook-queue"
+)
+
+func newDelivery(id string, at time.Time) *delivery {
+	return &delivery{ID: id, Subscription: "backend", Event: Event{ID: "e-" + id, Type: EventWorkspaceStopped, Timestamp: at}}
+}
+
+func marshal(d *delivery) string {
+	b, err := json.Marshal(d)
+	Expect(err).NotTo(HaveOccurred())
+	return string(b)
+}
+
+func ids(q *queue) []string {
+	var res []string
+	for _, d := range q.list() {
+		res = append(res, d.ID)
+	}
+	return res
+}
+
+// stored 返回ConfigMap中保存的投递任务ID
+func stored(c client.Client) []string {
+	cm := &v1.ConfigMap{}
+	Expect(c.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: queueName}, cm)).To(Succeed())
+	var res []string
+	for id := range cm.Data {
+		res = append(res, id)
+	}
+	return res
+}
+
+var _ = Describe("queue", func() {
+	var (
+		ctx context.Context
+		now time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		now = time.Now().Truncate(time.Second)
+	})
+
+	DescribeTable("load merges the ConfigMap with deliveries queued before loading",
+		func(existing map[string]string, queued []*delivery, expected []string) {
+			builder := fake.NewClientBuilder().WithScheme(scheme.Scheme)
+			if existing != nil {
+				builder = builder.WithObjects(&v1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: queueName},
+					Data:       existing,
+				})
+			}
+			c := builder.Build()
+			q := newQueue(c, c, namespace, queueName, 10)
+			for _, d := range queued {
+				q.put(d)
+			}
+			// 加载之前不写回
+			Expect(q.flush(ctx)).To(Succeed())
+			if existing == nil {
+				cm := &v1.ConfigMap{}
+				Expect(c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: queueName}, cm)).NotTo(Succeed())
+			}
+
+			Expect(q.load(ctx)).To(Succeed())
+			Expect(ids(q)).To(ConsistOf(expected))
+			Expect(q.flush(ctx)).To(Succeed())
+			Expect(stored(c)).To(ConsistOf(expected))
+		},
+		Entry("no ConfigMap", nil, []*delivery{newDelivery("a", time.Now())}, []string{"a"}),
+		Entry("restored deliveries",
+			map[string]string{"b": marshal(newDelivery("b", time.Now()))},
+			[]*delivery{newDelivery("a", time.Now())},
+			[]string{"a", "b"}),
+		Entry("corrupted deliveries dropped",
+			map[string]string{"b": marshal(newDelivery("b", time.Now())), "c": "{not json"},
+			nil,
+			[]string{"b"}),
+		Entry("deliveries queued before loading win",
+			map[string]string{"a": marshal(newDelivery("a", time.Now()))},
+			[]*delivery{newDelivery("a", time.Now())},
+			[]string{"a"}),
+	)
+
+	It("keeps a loaded delivery's state", func() {
+		d := newDelivery("a", now)
+		d.Attempts = 3
+		d.NextAttempt = now.Add(time.Minute)
+		c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(&v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: queueName},
+			Data:       map[string]string{"a": marshal(d)},
+		}).Build()
+		q := newQueue(c, c, namespace, queueName, 10)
+		Expect(q.load(ctx)).To(Succeed())
+
+		list := q.list()
+		Expect(list).To(HaveLen(1))
+		Expect(list[0].Attempts).To(Equal(3))
+		Expect(list[0].NextAttempt.Equal(d.NextAttempt)).To(BeTrue())
+	})
+
+	It("drops the oldest deliveries when full", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+		q := newQueue(c, c, namespace, queueName, 2)
+		q.put(newDelivery("b", now.Add(time.Second)))
+		q.put(newDelivery("a", now))
+		q.put(newDelivery("c", now.Add(2*time.Second)))
+		Expect(ids(q)).To(ConsistOf("b", "c"))
+	})
+
+	It("persists removals and ignores updates of removed deliveries", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+		q := newQueue(c, c, namespace, queueName, 10)
+		Expect(q.load(ctx)).To(Succeed())
+		a, b := newDelivery("a", now), newDelivery("b", now)
+		q.put(a)
+		q.put(b)
+		Expect(q.flush(ctx)).To(Succeed())
+		Expect(stored(c)).To(ConsistOf("a", "b"))
+
+		q.remove("a")
+		q.update(a)
+		Expect(q.flush(ctx)).To(Succeed())
+		Expect(stored(c)).To(ConsistOf("b"))
+	})
+})
@@ -0,0 +1,67 @@
+package workspace
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ListAuxiliary 列出工作空间的附属资源(Service、Secret、Ingress、PodDisruptionBudget)
+func ListAuxiliary(ctx context.Context, c client.Client, opts ...client.ListOption) ([]client.Object, error) {
+	var objects []client.Object
+	services := &v1.ServiceList{}
+	if err := c.List(ctx, services, opts...); err != nil {
+		return nil, err
+	}
+	for i := range services.Items {
+		objects = append(objects, &services.Items[i])
+	}
+	secrets := &v1.SecretList{}
+	if err := c.List(ctx, secrets, opts...); err != nil {
+		return nil, err
+	}
+	for i := range secrets.Items {
+		objects = append(objects, &secrets.Items[i])
+	}
+	ingresses := &networkingv1.IngressList{}
+	if err := c.List(ctx, ingresses, opts...); err != nil {
+		return nil, err
+	}
+	for i := range ingresses.Items {
+		objects = append(objects, &ingresses.Items[i])
+	}
+	pdbs := &policyv1.PodDisruptionBudgetList{}
+	if err := c.List(ctx, pdbs, opts...); err != nil {
+		return nil, err
+	}
+	for i := range pdbs.Items {
+		objects = append(objects, &pdbs.Items[i])
+	}
+
+	return objects, nil
+}
+
+// SetOwner 将工作空间的PVC设置为资源的owner,PVC删除时Kubernetes会回收这些资源
+func SetOwner(obj metav1.Object, pvc *v1.PersistentVolumeClaim) {
+	blockOwnerDeletion := true
+	ref := metav1.OwnerReference{
+		APIVersion:         "v1",
+		Kind:               "PersistentVolumeClaim",
+		Name:               pvc.Name,
+		UID:                pvc.UID,
+		BlockOwnerDeletion: &blockOwnerDeletion,
+	}
+	refs := obj.GetOwnerReferences()
+	for i := range refs {
+		if refs[i].UID == pvc.UID {
+			refs[i] = ref
+			obj.SetOwnerReferences(refs)
+			return
+		}
+	}
+	obj.SetOwnerReferences(append(refs, ref))
+}
@@ -0,0 +1,99 @@
+package workspace
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// 删除工作空间的进度
+const (
+	StageDeletingPod       = "deleting pod"
+	StageDeletingAuxiliary = "deleting services, secrets, ingresses and disruption budgets"
+	StageReleasingPVC      = "releasing pvc"
+	StageDeleted           = "deleted"
+)
+
+// Cleanup 按顺序删除工作空间的Pod和附属资源,返回当前所处的阶段,可以重复调用:
+// 先删除StatefulSet和Pod,Pod完全删除后再删除Service、Secret、Ingress、PodDisruptionBudget,全部删除后返回StageReleasingPVC,
+// 此时可以移除PVC上的finalizer
+func Cleanup(ctx context.Context, c client.Client, namespace, name string) (string, error) {
+	// StatefulSet会重建被删除的Pod,需要先删除,其Pod由Kubernetes在后台回收
+	sets := &appsv1.StatefulSetList{}
+	if err := c.List(ctx, sets, client.InNamespace(namespace), client.MatchingLabels{LabelWorkspace: name}); err != nil {
+		return "", err
+	}
+	for i := range sets.Items {
+		sts := &sets.Items[i]
+		if sts.DeletionTimestamp != nil {
+			continue
+		}
+		if err := c.Delete(ctx, sts, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+			return "", err
+		}
+	}
+
+	pods := &v1.PodList{}
+	if err := c.List(ctx, pods, client.InNamespace(namespace), client.MatchingLabels{LabelWorkspace: name}); err != nil {
+		return "", err
+	}
+	// 旧版本创建的Pod没有工作空间标签,Pod名称与工作空间相同
+	legacy := &v1.Pod{}
+	err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, legacy)
+	if err != nil && !errors.IsNotFound(err) {
+		return "", err
+	}
+	if err == nil && legacy.Labels[LabelWorkspace] == "" {
+		pods.Items = append(pods.Items, *legacy)
+	}
+	if len(sets.Items) > 0 || len(pods.Items) > 0 {
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			// StatefulSet的Pod随StatefulSet一起删除
+			if pod.DeletionTimestamp != nil || StatefulSetOf(pod) != "" {
+				continue
+			}
+			if err := DeletePod(ctx, c, pod, StopReasonDeleted); err != nil && !errors.IsNotFound(err) {
+				return "", err
+			}
+		}
+		return StageDeletingPod, nil
+	}
+
+	objects, err := ListAuxiliary(ctx, c, client.InNamespace(namespace), client.MatchingLabels{LabelWorkspace: name})
+	if err != nil {
+		return "", err
+	}
+	if len(objects) > 0 {
+		for _, obj := range objects {
+			if obj.GetDeletionTimestamp() != nil {
+				continue
+			}
+			if err := c.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) {
+				return "", err
+			}
+		}
+		return StageDeletingAuxiliary, nil
+	}
+
+	return StageReleasingPVC, nil
+}
+
+// DeletePVC 删除工作空间的PVC,删除前确保PVC上有finalizer,由PVCReconciler按顺序清理其它资源
+func DeletePVC(ctx context.Context, c client.Client, pvc *v1.PersistentVolumeClaim) error {
+	// 旧版本创建的PVC没有finalizer
+	if !controllerutil.ContainsFinalizer(pvc, FinalizerCleanup) {
+		controllerutil.AddFinalizer(pvc, FinalizerCleanup)
+		if err := c.Update(ctx, pvc); err != nil {
+			return err
+		}
+	}
+
+	// Foreground: Kubernetes先回收以PVC为owner的资源
+	return c.Delete(ctx, pvc, client.PropagationPolicy(metav1.DeletePropagationForeground))
+}
@@ -0,0 +1,171 @@
+package workspace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+/*
+	期望状态: 后端启动工作空间成功后在PVC上记录为running,主动停止工作空间(Stop、DeletePod)时记录为stopped,
+	Pod就绪后保存Pod模板,保存模板时不修改期望状态,以免覆盖刚刚记录的stopped。
+	期望running但Pod消失或失败的工作空间由自动恢复按保存的模板重新创建,重启历史也保存在PVC上
+*/
+
+const (
+	// AnnotationDesiredState 工作空间的期望状态
+	AnnotationDesiredState = "cloud-ide.mangohow.com/desired-state"
+	// AnnotationPodTemplate 工作空间最近一次就绪的Pod模板,JSON格式
+	AnnotationPodTemplate = "cloud-ide.mangohow.com/pod-template"
+	// AnnotationRestartHistory 自动重启的历史,JSON格式
+	AnnotationRestartHistory = "cloud-ide.mangohow.com/restart-history"
+
+	DesiredRunning = "running"
+	DesiredStopped = "stopped"
+
+	// restartHistoryLimit 保留的重启记录数量
+	restartHistoryLimit = 10
+)
+
+// StatefulSet控制器添加到Pod上的标签,不属于模板
+var statefulSetPodLabels = []string{
+	"controller-revision-hash",
+	"statefulset.kubernetes.io/pod-name",
+	"apps.kubernetes.io/pod-index",
+}
+
+// RestartRecord 一次自动重启
+type RestartRecord struct {
+	Time     metav1.Time `json:"time"`
+	Reason   string      `json:"reason"`
+	NodeName string      `json:"nodeName,omitempty"`
+}
+
+// PodTemplate 根据运行中的Pod生成可以重新创建的模板,名称为工作空间名称,去掉调度结果和由控制器添加的字段
+func PodTemplate(pod *v1.Pod) *v1.Pod {
+	labels := make(map[string]string, len(pod.Labels))
+	for k, v := range pod.Labels {
+		labels[k] = v
+	}
+	for _, k := range statefulSetPodLabels {
+		delete(labels, k)
+	}
+
+	tpl := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      Name(pod),
+			Namespace: pod.Namespace,
+			Labels:    labels,
+		},
+		Spec: *pod.Spec.DeepCopy(),
+	}
+	tpl.Spec.NodeName = ""
+	tpl.Spec.Hostname = ""
+	tpl.Spec.Subdomain = ""
+
+	return tpl
+}
+
+// DesiredTemplate 返回PVC上保存的Pod模板,没有保存时返回nil
+func DesiredTemplate(pvc *v1.PersistentVolumeClaim) (*v1.Pod, error) {
+	data := pvc.Annotations[AnnotationPodTemplate]
+	if data == "" {
+		return nil, nil
+	}
+	pod := &v1.Pod{}
+	if err := json.Unmarshal([]byte(data), pod); err != nil {
+		return nil, err
+	}
+
+	return pod, nil
+}
+
+// SetDesiredRunning 记录工作空间期望运行,由后端在启动工作空间成功后调用
+func SetDesiredRunning(ctx context.Context, c client.Client, namespace, claimName string) error {
+	pvc := &v1.PersistentVolumeClaim{}
+	pvc.Name = claimName
+	pvc.Namespace = namespace
+	patch := []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, AnnotationDesiredState, DesiredRunning))
+
+	return c.Patch(ctx, pvc, client.RawPatch(types.MergePatchType, patch))
+}
+
+// RecordTemplate 保存期望运行的工作空间的Pod模板,与PVC上已有的模板相同时不更新。
+// 使用resourceVersion乐观锁,PVC已经被记录为stopped(缓存可能还没有同步)时返回Conflict;
+// 没有期望状态的旧工作空间同时记录为running
+func RecordTemplate(ctx context.Context, c client.Client, pvc *v1.PersistentVolumeClaim, pod *v1.Pod) error {
+	state, ok := pvc.Annotations[AnnotationDesiredState]
+	if ok && state != DesiredRunning {
+		return nil
+	}
+	data, err := json.Marshal(PodTemplate(pod))
+	if err != nil {
+		return err
+	}
+	if ok && pvc.Annotations[AnnotationPodTemplate] == string(data) {
+		return nil
+	}
+	base := pvc.DeepCopy()
+	if pvc.Annotations == nil {
+		pvc.Annotations = make(map[string]string)
+	}
+	pvc.Annotations[AnnotationPodTemplate] = string(data)
+	if !ok {
+		pvc.Annotations[AnnotationDesiredState] = DesiredRunning
+	}
+
+	return c.Patch(ctx, pvc, client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{}))
+}
+
+// SetDesiredStopped 记录工作空间期望停止,自动恢复不再处理该工作空间
+func SetDesiredStopped(ctx context.Context, c client.Client, namespace, claimName string) error {
+	pvc := &v1.PersistentVolumeClaim{}
+	pvc.Name = claimName
+	pvc.Namespace = namespace
+	patch := []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, AnnotationDesiredState, DesiredStopped))
+
+	return c.Patch(ctx, pvc, client.RawPatch(types.MergePatchType, patch))
+}
+
+// RestartHistory 返回PVC上记录的自动重启历史,按时间顺序
+func RestartHistory(pvc *v1.PersistentVolumeClaim) []RestartRecord {
+	var records []RestartRecord
+	if data := pvc.Annotations[AnnotationRestartHistory]; data != "" {
+		// 格式错误的记录忽略
+		_ = json.Unmarshal([]byte(data), &records)
+	}
+
+	return records
+}
+
+// AddRestart 在PVC上追加一条自动重启记录,只保留最近的记录
+func AddRestart(ctx context.Context, c client.Client, pvc *v1.PersistentVolumeClaim, reason, nodeName string) error {
+	records := append(RestartHistory(pvc), RestartRecord{
+		Time:     metav1.NewTime(time.Now()),
+		Reason:   reason,
+		NodeName: nodeName,
+	})
+	if len(records) > restartHistoryLimit {
+		records = records[len(records)-restartHistoryLimit:]
+	}
+	data, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{AnnotationRestartHistory: string(data)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.Patch(ctx, pvc, client.RawPatch(types.MergePatchType, patch))
+}
@@ -0,0 +1,49 @@
+package workspace
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// AnnotationDevcontainer 工作空间使用的devcontainer.json,已经去除注释
+	AnnotationDevcontainer = "cloud-ide.mangohow.com/devcontainer"
+	// AnnotationDevcontainerPath 从克隆的仓库中读取devcontainer.json的路径,读取后保存到AnnotationDevcontainer
+	AnnotationDevcontainerPath = "cloud-ide.mangohow.com/devcontainer-path"
+)
+
+// ClonedDevcontainer 返回git-clone init容器从仓库中读取的devcontainer.json,
+// 它跟在终止消息的第一行之后,没有读取时返回空
+func ClonedDevcontainer(pod *v1.Pod) string {
+	for _, cs := range pod.Status.InitContainerStatuses {
+		if cs.Name != GitCloneContainer || cs.State.Terminated == nil {
+			continue
+		}
+		parts := strings.SplitN(cs.State.Terminated.Message, "\n", 2)
+		if len(parts) < 2 || !strings.HasPrefix(parts[0], GitClonedPrefix) {
+			return ""
+		}
+		return strings.TrimSpace(parts[1])
+	}
+
+	return ""
+}
+
+// SetDevcontainer 在PVC上保存devcontainer.json,下次启动时生效
+func SetDevcontainer(ctx context.Context, c client.Client, pvc *v1.PersistentVolumeClaim, data string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{AnnotationDevcontainer: data},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.Patch(ctx, pvc, client.RawPatch(types.MergePatchType, patch))
+}
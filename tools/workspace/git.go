@@ -0,0 +1,129 @@
+package workspace
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+/*
+	Git仓库克隆: 创建工作空间时的Git源保存在PVC上,每次启动时如果还没有克隆成功,
+	由git-clone init容器克隆到存储卷中。克隆失败不影响工作空间启动,
+	init容器的终止消息以Cloned或Failed开头,PodReconciler据此在PVC上记录GitCloned条件,
+	克隆成功后不再添加init容器
+*/
+
+const (
+	// AnnotationGitSource 创建工作空间时指定的Git源,JSON格式
+	AnnotationGitSource = "cloud-ide.mangohow.com/git-source"
+	// AnnotationGitCondition 克隆的结果,JSON格式的GitCloned条件
+	AnnotationGitCondition = "cloud-ide.mangohow.com/git-condition"
+
+	// GitCloneContainer 克隆仓库的init容器名称
+	GitCloneContainer = "git-clone"
+	// ConditionGitCloned 仓库是否已经克隆到存储卷中
+	ConditionGitCloned = "GitCloned"
+
+	// init容器终止消息的前缀
+	GitClonedPrefix = "Cloned"
+	GitFailedPrefix = "Failed"
+
+	// KindGitCredentials 用户的Git凭证Secret的kind标签。凭证会被发送到调用方指定的仓库地址,
+	// 因此只接受带有该标签、并且LabelOwner是工作空间所有者的Secret
+	KindGitCredentials = "cloud-ide-git-credentials"
+)
+
+// GitSource 工作空间的Git源
+type GitSource struct {
+	URL string `json:"url"`
+	// 分支、tag或commit,为空时使用默认分支
+	Ref string `json:"ref,omitempty"`
+	// 克隆到存储卷中的子目录
+	Directory string `json:"directory"`
+	// 保存凭证的Secret,包含username和password,或ssh-privatekey和可选的known_hosts
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// GetGitSource 返回PVC上保存的Git源,没有时返回nil
+func GetGitSource(pvc *v1.PersistentVolumeClaim) *GitSource {
+	data := pvc.Annotations[AnnotationGitSource]
+	if data == "" {
+		return nil
+	}
+	src := &GitSource{}
+	if err := json.Unmarshal([]byte(data), src); err != nil {
+		return nil
+	}
+
+	return src
+}
+
+// GitCondition 返回PVC上记录的克隆结果,还没有结果时返回nil
+func GitCondition(pvc *v1.PersistentVolumeClaim) *metav1.Condition {
+	data := pvc.Annotations[AnnotationGitCondition]
+	if data == "" {
+		return nil
+	}
+	cond := &metav1.Condition{}
+	if err := json.Unmarshal([]byte(data), cond); err != nil {
+		return nil
+	}
+
+	return cond
+}
+
+// GitCloned 仓库是否已经克隆成功
+func GitCloned(pvc *v1.PersistentVolumeClaim) bool {
+	cond := GitCondition(pvc)
+	return cond != nil && cond.Status == metav1.ConditionTrue
+}
+
+// GitCloneResult 根据git-clone init容器的终止消息判断克隆结果,init容器还没有结束时done为false
+func GitCloneResult(pod *v1.Pod) (cond metav1.Condition, done bool) {
+	for _, cs := range pod.Status.InitContainerStatuses {
+		if cs.Name != GitCloneContainer || cs.State.Terminated == nil {
+			continue
+		}
+		// 第一行是克隆结果,之后是从仓库中读取的devcontainer.json
+		message := strings.TrimSpace(strings.SplitN(cs.State.Terminated.Message, "\n", 2)[0])
+		cond = metav1.Condition{
+			Type:    ConditionGitCloned,
+			Status:  metav1.ConditionFalse,
+			Reason:  "CloneFailed",
+			Message: message,
+		}
+		if cs.State.Terminated.ExitCode == 0 && strings.HasPrefix(message, GitClonedPrefix) {
+			cond.Status, cond.Reason = metav1.ConditionTrue, "Cloned"
+		}
+		return cond, true
+	}
+
+	return cond, false
+}
+
+// SetGitCondition 在PVC上记录克隆结果,与已有的结果相同时不更新
+func SetGitCondition(ctx context.Context, c client.Client, pvc *v1.PersistentVolumeClaim, cond metav1.Condition) (bool, error) {
+	if old := GitCondition(pvc); old != nil && old.Status == cond.Status && old.Message == cond.Message {
+		return false, nil
+	}
+	cond.LastTransitionTime = metav1.Now()
+	data, err := json.Marshal(cond)
+	if err != nil {
+		return false, err
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{AnnotationGitCondition: string(data)},
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return true, c.Patch(ctx, pvc, client.RawPatch(types.MergePatchType, patch))
+}
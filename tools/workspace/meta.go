@@ -0,0 +1,49 @@
+package workspace
+
+// 工作空间相关资源上使用的标签
+const (
+	LabelKind    = "kind"
+	KindCloudIde = "cloud-ide"
+	// LabelWorkspace 资源所属的工作空间名称,Pod、PVC以及其它附属资源都会设置该标签
+	LabelWorkspace = "cloud-ide.mangohow.com/workspace"
+	// LabelState 工作空间的状态,目前只有trashed(已删除,保留在回收站中)
+	LabelState   = "cloud-ide.mangohow.com/state"
+	StateTrashed = "trashed"
+)
+
+// 工作空间相关资源上使用的注解
+const (
+	// AnnotationInitialized PVC对应的工作空间至少成功启动过一次
+	AnnotationInitialized = "cloud-ide.mangohow.com/initialized"
+	// AnnotationStopReason 控制器主动删除Pod时记录的原因,没有该注解的Pod被删除说明工作空间是自己停止的
+	AnnotationStopReason = "cloud-ide.mangohow.com/stop-reason"
+	// AnnotationTrashedAt 工作空间移入回收站的时间,RFC3339格式
+	AnnotationTrashedAt = "cloud-ide.mangohow.com/trashed-at"
+	// AnnotationPurgeAt 回收站中的工作空间计划彻底删除的时间,RFC3339格式
+	AnnotationPurgeAt = "cloud-ide.mangohow.com/purge-at"
+	// AnnotationImage 管理员升级后工作空间使用的镜像,启动时优先于调用方请求的镜像
+	AnnotationImage = "cloud-ide.mangohow.com/image"
+	// AnnotationDrainNotice 节点被封锁后通知用户的时间,RFC3339格式,工作空间在通知期结束后迁移
+	AnnotationDrainNotice = "cloud-ide.mangohow.com/drain-notice"
+)
+
+// FinalizerCleanup PVC上的finalizer,PVC删除时先按顺序删除Pod和附属资源,再移除该finalizer
+const FinalizerCleanup = "cloud-ide.mangohow.com/workspace-cleanup"
+
+// 控制器主动停止工作空间的原因
+const (
+	StopReasonRequested    = "StopRequested"
+	StopReasonStartTimeout = "StartTimeout"
+	StopReasonNotRunning   = "NotRunning"
+	StopReasonGarbage      = "GarbageCollected"
+	StopReasonDeleted      = "WorkspaceDeleted"
+	StopReasonTrashed      = "WorkspaceTrashed"
+	StopReasonUpgrade      = "ImageUpgrade"
+	// 自动恢复删除失败的Pod,随后重新创建
+	StopReasonHeal = "AutoHeal"
+	// 节点维护,工作空间迁移到其它节点
+	StopReasonDrain = "NodeDrain"
+	// 排队等待资源超时或被取消
+	StopReasonQueueTimeout  = "QueueTimeout"
+	StopReasonQueueCanceled = "QueueCanceled"
+)
@@ -0,0 +1,78 @@
+package workspace
+
+import (
+	"context"
+	"encoding/json"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+/*
+	已通知状态: 发送Running、Stopped通知后记录在PVC上,控制器重启后据此判断Pod的Running是否已经通知过,
+	以及控制器停止期间消失的Pod是否还需要补发Stopped通知
+*/
+
+const (
+	// AnnotationNotifiedState 最近一次通知的工作空间状态,JSON格式
+	AnnotationNotifiedState = "cloud-ide.mangohow.com/notified-state"
+
+	NotifiedRunning = "running"
+	NotifiedStopped = "stopped"
+)
+
+// NotifiedState 最近一次通知的工作空间状态
+type NotifiedState struct {
+	State    string    `json:"state"`
+	PodUID   types.UID `json:"podUID,omitempty"`
+	NodeName string    `json:"nodeName,omitempty"`
+}
+
+// GetNotifiedState 返回PVC上记录的已通知状态,没有记录或格式错误时返回false
+func GetNotifiedState(pvc *v1.PersistentVolumeClaim) (NotifiedState, bool) {
+	var st NotifiedState
+	data := pvc.Annotations[AnnotationNotifiedState]
+	if data == "" || json.Unmarshal([]byte(data), &st) != nil {
+		return NotifiedState{}, false
+	}
+
+	return st, true
+}
+
+// SetNotifiedState 在PVC上记录已通知状态
+func SetNotifiedState(ctx context.Context, c client.Client, namespace, claimName string, st NotifiedState) error {
+	pvc := &v1.PersistentVolumeClaim{}
+	pvc.Name = claimName
+	pvc.Namespace = namespace
+	data, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{AnnotationNotifiedState: string(data)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.Patch(ctx, pvc, client.RawPatch(types.MergePatchType, patch))
+}
+
+// MarkNotifiedStopped 将PVC记录为已通知停止,使用resourceVersion乐观锁,
+// PVC在读取后被修改(例如调谐时已经通知了停止)时返回Conflict
+func MarkNotifiedStopped(ctx context.Context, c client.Client, pvc *v1.PersistentVolumeClaim, nodeName string) error {
+	data, err := json.Marshal(NotifiedState{State: NotifiedStopped, NodeName: nodeName})
+	if err != nil {
+		return err
+	}
+	base := pvc.DeepCopy()
+	if pvc.Annotations == nil {
+		pvc.Annotations = make(map[string]string)
+	}
+	pvc.Annotations[AnnotationNotifiedState] = string(data)
+
+	return c.Patch(ctx, pvc, client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{}))
+}
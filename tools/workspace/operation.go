@@ -0,0 +1,96 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+/*
+	正在进行中的操作保存在Pod的注解中,而不是只保存在等待Pod就绪的goroutine中,
+	这样控制器重启后,PodReconciler会在第一次同步时重新处理所有Pod:
+	截止时间之前就绪的Pod删除注解,超过截止时间仍未就绪的Pod被删除,
+	无论控制器是否重启过,超时的工作空间都以相同的方式清理
+*/
+
+const (
+	// AnnotationOperation 正在进行中的操作
+	AnnotationOperation = "cloud-ide.mangohow.com/operation"
+	// AnnotationDeadline 操作的截止时间,RFC3339格式
+	AnnotationDeadline = "cloud-ide.mangohow.com/deadline"
+
+	OperationStarting = "starting"
+)
+
+// SetStarting 标记Pod正在启动,需要在deadline之前就绪
+func SetStarting(pod *v1.Pod, deadline time.Time) {
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	pod.Annotations[AnnotationOperation] = OperationStarting
+	pod.Annotations[AnnotationDeadline] = deadline.UTC().Format(time.RFC3339)
+}
+
+// StartDeadline 如果Pod正在启动,返回启动的截止时间
+func StartDeadline(pod *v1.Pod) (time.Time, bool) {
+	if pod.Annotations[AnnotationOperation] != OperationStarting {
+		return time.Time{}, false
+	}
+	deadline, err := time.Parse(time.RFC3339, pod.Annotations[AnnotationDeadline])
+	if err != nil {
+		// 截止时间无法解析,认为已经超时
+		return time.Time{}, true
+	}
+	// StatefulSet按模板重建的Pod会带上模板中的注解,截止时间之后创建的Pod不属于这次启动
+	if pod.CreationTimestamp.After(deadline) {
+		return time.Time{}, false
+	}
+
+	return deadline, true
+}
+
+// FinishOperation Pod的操作已经完成,删除操作相关的注解
+func FinishOperation(ctx context.Context, c client.Client, pod *v1.Pod) error {
+	patch := []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:null,%q:null}}}`, AnnotationOperation, AnnotationDeadline))
+
+	return c.Patch(ctx, pod, client.RawPatch(types.MergePatchType, patch))
+}
+
+// DeletePod 删除Pod,reason记录在Pod的注解中,用于区分工作空间是主动停止还是自己停止,
+// 同时将工作空间记录为期望停止,避免被自动恢复重新创建。
+// StatefulSet管理的Pod删除后会被重建,改为将副本数缩为0
+func DeletePod(ctx context.Context, c client.Client, pod *v1.Pod, reason string) error {
+	if pod.UID != "" {
+		patch := []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, AnnotationStopReason, reason))
+		if err := c.Patch(ctx, pod, client.RawPatch(types.MergePatchType, patch)); err != nil {
+			return err
+		}
+	}
+	if claim := ClaimName(pod); claim != "" {
+		if err := SetDesiredStopped(ctx, c, pod.Namespace, claim); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	if name := StatefulSetOf(pod); name != "" {
+		sts := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: pod.Namespace}}
+		return c.Patch(ctx, sts, client.RawPatch(types.MergePatchType, []byte(`{"spec":{"replicas":0}}`)))
+	}
+
+	return c.Delete(ctx, pod)
+}
+
+// MarkInitialized 标记PVC对应的工作空间已经成功启动过
+func MarkInitialized(ctx context.Context, c client.Client, namespace, claimName string) error {
+	pvc := &v1.PersistentVolumeClaim{}
+	pvc.Name = claimName
+	pvc.Namespace = namespace
+	patch := []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:"true"}}}`, AnnotationInitialized))
+
+	return c.Patch(ctx, pvc, client.RawPatch(types.MergePatchType, patch))
+}
@@ -0,0 +1,42 @@
+package workspace
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=create
+
+// EnsurePDB 为工作空间创建不允许驱逐的PodDisruptionBudget,节点排空时由控制器通知用户后迁移工作空间,
+// PDB与PVC同名,以PVC为owner,已经存在时不做任何修改
+func EnsurePDB(ctx context.Context, c client.Client, pvc *v1.PersistentVolumeClaim) error {
+	maxUnavailable := intstr.FromInt(0)
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pvc.Name,
+			Namespace: pvc.Namespace,
+			Labels: map[string]string{
+				LabelKind:      KindCloudIde,
+				LabelWorkspace: pvc.Name,
+			},
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MaxUnavailable: &maxUnavailable,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{LabelWorkspace: pvc.Name},
+			},
+		},
+	}
+	SetOwner(pdb, pvc)
+	if err := c.Create(ctx, pdb); err != nil && !errors.IsAlreadyExists(err) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package workspace
+
+import (
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IsPodReady Pod的Ready条件为True时,所有容器都已经通过就绪探针
+func IsPodReady(pod *v1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == v1.PodReady {
+			return cond.Status == v1.ConditionTrue
+		}
+	}
+
+	return false
+}
+
+// Name 返回Pod所属的工作空间名称,StatefulSet创建的Pod名称带有序号,因此优先使用工作空间标签
+func Name(pod *v1.Pod) string {
+	if name := pod.Labels[LabelWorkspace]; name != "" {
+		return name
+	}
+
+	return pod.Name
+}
+
+// Container 返回运行code-server的容器,其名称与工作空间相同
+func Container(pod *v1.Pod) *v1.Container {
+	name := Name(pod)
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name == name {
+			return &pod.Spec.Containers[i]
+		}
+	}
+	if len(pod.Spec.Containers) > 0 {
+		return &pod.Spec.Containers[0]
+	}
+
+	return nil
+}
+
+// SetGracefulStop 设置Pod的优雅终止时间和code-server容器的preStop命令,Pod被删除时code-server可以保存状态
+func SetGracefulStop(pod *v1.Pod, grace time.Duration, preStop []string) {
+	if grace > 0 {
+		seconds := int64(grace / time.Second)
+		pod.Spec.TerminationGracePeriodSeconds = &seconds
+	}
+	c := Container(pod)
+	if c == nil || len(preStop) == 0 {
+		return
+	}
+	if c.Lifecycle == nil {
+		c.Lifecycle = &v1.Lifecycle{}
+	}
+	c.Lifecycle.PreStop = &v1.LifecycleHandler{Exec: &v1.ExecAction{Command: preStop}}
+}
+
+// StatefulSetOf 返回管理该Pod的StatefulSet名称,直接创建的Pod返回空
+func StatefulSetOf(pod *v1.Pod) string {
+	if ref := metav1.GetControllerOf(pod); ref != nil && ref.Kind == "StatefulSet" {
+		return ref.Name
+	}
+
+	return ""
+}
+
+// ClaimName 返回Pod挂载的工作空间PVC名称
+func ClaimName(pod *v1.Pod) string {
+	for _, vol := range pod.Spec.Volumes {
+		if vol.PersistentVolumeClaim != nil {
+			return vol.PersistentVolumeClaim.ClaimName
+		}
+	}
+
+	return ""
+}
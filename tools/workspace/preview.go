@@ -0,0 +1,156 @@
+package workspace
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+/*
+	端口预览: 每个工作空间一个Service,包含所有暴露的端口;每个暴露的端口一个Ingress,
+	Ingress上记录端口名称和可见性,是暴露状态的唯一来源。两者都是工作空间的附属资源,随工作空间一起删除
+*/
+
+const (
+	// LabelPreviewPort 预览Ingress暴露的端口
+	LabelPreviewPort = "cloud-ide.mangohow.com/preview-port"
+	// AnnotationPreviewName 暴露时指定的端口名称
+	AnnotationPreviewName = "cloud-ide.mangohow.com/preview-name"
+	// AnnotationPreviewVisibility 预览的可见性
+	AnnotationPreviewVisibility = "cloud-ide.mangohow.com/preview-visibility"
+
+	VisibilityPrivate = "private"
+	VisibilityShared  = "shared"
+)
+
+//+kubebuilder:rbac:groups="",resources=services,verbs=create;update
+//+kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=create;update
+
+// PreviewServiceName 工作空间预览Service的名称,名称过长时使用哈希
+func PreviewServiceName(name string) string {
+	if n := "preview-" + name; len(n) <= 63 {
+		return n
+	}
+	sum := sha256.Sum256([]byte(name))
+
+	return "preview-" + hex.EncodeToString(sum[:8])
+}
+
+// PreviewIngressName 暴露端口的Ingress名称
+func PreviewIngressName(name string, port int32) string {
+	return fmt.Sprintf("%s-%d", PreviewServiceName(name), port)
+}
+
+// ListPreviews 列出工作空间暴露端口的Ingress,按端口排序
+func ListPreviews(ctx context.Context, c client.Reader, namespace, name string) ([]networkingv1.Ingress, error) {
+	ingresses := &networkingv1.IngressList{}
+	err := c.List(ctx, ingresses, client.InNamespace(namespace),
+		client.MatchingLabels{LabelWorkspace: name}, client.HasLabels{LabelPreviewPort})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(ingresses.Items, func(i, j int) bool {
+		return PreviewPort(&ingresses.Items[i]) < PreviewPort(&ingresses.Items[j])
+	})
+
+	return ingresses.Items, nil
+}
+
+// PreviewPort 预览Ingress暴露的端口
+func PreviewPort(ing *networkingv1.Ingress) int32 {
+	port, _ := strconv.Atoi(ing.Labels[LabelPreviewPort])
+	return int32(port)
+}
+
+// SyncPreviewService 将预览Service的端口更新为ports,ports为空时删除Service
+func SyncPreviewService(ctx context.Context, c client.Client, pvc *v1.PersistentVolumeClaim, ports []int32) error {
+	svc := &v1.Service{}
+	key := client.ObjectKey{Namespace: pvc.Namespace, Name: PreviewServiceName(pvc.Name)}
+	err := c.Get(ctx, key, svc)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+	if len(ports) == 0 {
+		if exists {
+			return client.IgnoreNotFound(c.Delete(ctx, svc))
+		}
+		return nil
+	}
+
+	if !exists {
+		svc = &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+				Labels:    map[string]string{LabelKind: KindCloudIde, LabelWorkspace: pvc.Name},
+			},
+		}
+		SetOwner(svc, pvc)
+	}
+	svc.Spec.Selector = map[string]string{LabelWorkspace: pvc.Name}
+	svc.Spec.Ports = svc.Spec.Ports[:0]
+	for _, port := range ports {
+		svc.Spec.Ports = append(svc.Spec.Ports, v1.ServicePort{
+			Name:       fmt.Sprintf("p-%d", port),
+			Port:       port,
+			TargetPort: intstr.FromInt(int(port)),
+		})
+	}
+	if exists {
+		return c.Update(ctx, svc)
+	}
+
+	return c.Create(ctx, svc)
+}
+
+// PreviewIngress 构造暴露端口的Ingress,tlsSecret为空时不配置TLS
+func PreviewIngress(pvc *v1.PersistentVolumeClaim, port int32, host, className, tlsSecret string) *networkingv1.Ingress {
+	pathType := networkingv1.PathTypePrefix
+	ing := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      PreviewIngressName(pvc.Name, port),
+			Namespace: pvc.Namespace,
+			Labels: map[string]string{
+				LabelKind:        KindCloudIde,
+				LabelWorkspace:   pvc.Name,
+				LabelPreviewPort: strconv.Itoa(int(port)),
+			},
+			Annotations: map[string]string{},
+		},
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{{
+				Host: host,
+				IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{{
+						Path:     "/",
+						PathType: &pathType,
+						Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+							Name: PreviewServiceName(pvc.Name),
+							Port: networkingv1.ServiceBackendPort{Number: port},
+						}},
+					}},
+				}},
+			}},
+		},
+	}
+	if className != "" {
+		ing.Spec.IngressClassName = &className
+	}
+	if tlsSecret != "" {
+		ing.Spec.TLS = []networkingv1.IngressTLS{{Hosts: []string{host}, SecretName: tlsSecret}}
+	}
+	SetOwner(ing, pvc)
+
+	return ing
+}
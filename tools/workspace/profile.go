@@ -0,0 +1,48 @@
+package workspace
+
+import (
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"strings"
+)
+
+/*
+	用户配置: 每个用户(owner)的dotfiles和code-server配置保存在一个Secret中,
+	Secret中的key是文件相对路径的base32编码,工作空间启动时由init容器同步到存储卷中,
+	用户在工作空间中的修改会在下次启动时被配置中的内容覆盖
+*/
+
+const (
+	// LabelOwner 工作空间或用户配置所属的用户
+	LabelOwner = "cloud-ide.mangohow.com/owner"
+	// KindProfile 用户配置Secret的kind标签,与工作空间的资源区分,不会被垃圾回收
+	KindProfile = "cloud-ide-profile"
+	// AnnotationProfileUpdatedAt 用户配置最后一次更新的时间,RFC3339格式
+	AnnotationProfileUpdatedAt = "cloud-ide.mangohow.com/profile-updated-at"
+)
+
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=create;update
+
+var profileEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// ProfileSecretName 用户配置Secret的名称,用户名不一定是合法的资源名称,因此使用哈希
+func ProfileSecretName(owner string) string {
+	sum := sha256.Sum256([]byte(owner))
+	return "profile-" + hex.EncodeToString(sum[:8])
+}
+
+// ProfileKey 文件路径在Secret中的key
+func ProfileKey(path string) string {
+	return strings.ToLower(profileEncoding.EncodeToString([]byte(path)))
+}
+
+// ProfilePath 根据Secret中的key返回文件路径
+func ProfilePath(key string) (string, bool) {
+	path, err := profileEncoding.DecodeString(strings.ToUpper(key))
+	if err != nil {
+		return "", false
+	}
+
+	return string(path), true
+}
@@ -0,0 +1,46 @@
+package workspace
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// KindSSHKeys 用户SSH公钥Secret的kind标签,不会被垃圾回收
+	KindSSHKeys = "cloud-ide-ssh-keys"
+	// SSHKeysDataKey Secret中保存公钥的key,authorized_keys格式,每行一个
+	SSHKeysDataKey = "authorized_keys"
+)
+
+// SSHKeysSecretName 用户SSH公钥Secret的名称
+func SSHKeysSecretName(owner string) string {
+	sum := sha256.Sum256([]byte(owner))
+	return "sshkeys-" + hex.EncodeToString(sum[:8])
+}
+
+// SSHKeys 返回用户注册的公钥,没有注册时返回nil
+func SSHKeys(ctx context.Context, c client.Reader, namespace, owner string) ([]string, error) {
+	secret := &v1.Secret{}
+	err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: SSHKeysSecretName(owner)}, secret)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var keys []string
+	for _, line := range strings.Split(string(secret.Data[SSHKeysDataKey]), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			keys = append(keys, line)
+		}
+	}
+
+	return keys, nil
+}